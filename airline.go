@@ -0,0 +1,410 @@
+package messenger
+
+import (
+	"context"
+	"regexp"
+)
+
+type AirlineUpdateType string
+
+const (
+	AirlineUpdateTypeDelay        = AirlineUpdateType("delay")
+	AirlineUpdateTypeGateChange   = AirlineUpdateType("gate_change")
+	AirlineUpdateTypeCancellation = AirlineUpdateType("cancellation")
+)
+
+// Limits of the airline templates
+const (
+	airlineMaxAuxiliaryFields = 5
+	airlineMaxSecondaryFields = 5
+	airlineMaxPriceInfo       = 4
+	airlineMaxProductInfo     = 4
+)
+
+var themeColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+type (
+	// AirlineBoardingPassTemplate sends one or more boarding passes.
+	// https://developers.facebook.com/docs/messenger-platform/reference/templates/airline-boarding-pass
+	AirlineBoardingPassTemplate struct {
+		IntroMessage   string // required
+		Locale         string // required, e.g. "en_US"
+		ThemeColor     string // e.g. "#009ddc"
+		BoardingPasses []AirlineBoardingPass
+	}
+
+	// AirlineItineraryTemplate sends the itinerary of a booking.
+	// https://developers.facebook.com/docs/messenger-platform/reference/templates/airline-itinerary
+	AirlineItineraryTemplate struct {
+		IntroMessage         string // required
+		Locale               string // required
+		ThemeColor           string
+		PNRNumber            string // required
+		PassengerInfo        []AirlinePassengerInfo
+		FlightInfo           []AirlineFlightInfo // ConnectionID and SegmentID are required
+		PassengerSegmentInfo []AirlinePassengerSegmentInfo
+		PriceInfo            []AirlinePriceInfo // up to 4 elements
+		BasePrice            string
+		Tax                  string
+		TotalPrice           string // required
+		Currency             string // required, ISO 4217 code, e.g. "USD"
+	}
+
+	// AirlineCheckinTemplate sends a check-in reminder.
+	// https://developers.facebook.com/docs/messenger-platform/reference/templates/airline-check-in
+	AirlineCheckinTemplate struct {
+		IntroMessage string // required
+		Locale       string // required
+		ThemeColor   string
+		PNRNumber    string // required
+		CheckinURL   string // required
+		FlightInfo   []AirlineFlightInfo
+	}
+
+	// AirlineUpdateTemplate sends a flight status update.
+	// https://developers.facebook.com/docs/messenger-platform/reference/templates/airline-flight-update
+	AirlineUpdateTemplate struct {
+		IntroMessage     string // required
+		Locale           string // required
+		ThemeColor       string
+		UpdateType       AirlineUpdateType // required
+		PNRNumber        string            // required
+		UpdateFlightInfo AirlineFlightInfo
+	}
+
+	// AirlineBoardingPass is the boarding pass of a passenger for a flight, exactly one of QRCode and
+	// BarcodeImageURL must be set.
+	AirlineBoardingPass struct {
+		PassengerName        string            `json:"passenger_name"`
+		PNRNumber            string            `json:"pnr_number"`
+		TravelClass          string            `json:"travel_class,omitempty"`
+		Seat                 string            `json:"seat,omitempty"`
+		AuxiliaryFields      []AirlineField    `json:"auxiliary_fields,omitempty"`
+		SecondaryFields      []AirlineField    `json:"secondary_fields,omitempty"`
+		LogoImageURL         string            `json:"logo_image_url"`
+		HeaderImageURL       string            `json:"header_image_url,omitempty"`
+		HeaderTextField      *AirlineField     `json:"header_text_field,omitempty"`
+		QRCode               string            `json:"qr_code,omitempty"`
+		BarcodeImageURL      string            `json:"barcode_image_url,omitempty"`
+		AboveBarcodeImageURL string            `json:"above_bar_code_image_url"`
+		FlightInfo           AirlineFlightInfo `json:"flight_info"`
+	}
+
+	AirlineField struct {
+		Label string `json:"label"`
+		Value string `json:"value"`
+	}
+
+	AirlineFlightInfo struct {
+		ConnectionID     string                `json:"connection_id,omitempty"`
+		SegmentID        string                `json:"segment_id,omitempty"`
+		FlightNumber     string                `json:"flight_number"`
+		AircraftType     string                `json:"aircraft_type,omitempty"`
+		DepartureAirport AirlineAirport        `json:"departure_airport"`
+		ArrivalAirport   AirlineAirport        `json:"arrival_airport"`
+		FlightSchedule   AirlineFlightSchedule `json:"flight_schedule"`
+		TravelClass      string                `json:"travel_class,omitempty"`
+	}
+
+	AirlineAirport struct {
+		AirportCode string `json:"airport_code"`
+		City        string `json:"city"`
+		Terminal    string `json:"terminal,omitempty"`
+		Gate        string `json:"gate,omitempty"`
+	}
+
+	// AirlineFlightSchedule holds the times of a flight, in ISO 8601 format, e.g. "2016-01-05T15:05"
+	AirlineFlightSchedule struct {
+		BoardingTime  string `json:"boarding_time,omitempty"`
+		DepartureTime string `json:"departure_time"`
+		ArrivalTime   string `json:"arrival_time,omitempty"`
+	}
+
+	AirlinePassengerInfo struct {
+		PassengerID  string `json:"passenger_id"`
+		TicketNumber string `json:"ticket_number,omitempty"`
+		Name         string `json:"name"`
+	}
+
+	AirlinePassengerSegmentInfo struct {
+		SegmentID   string               `json:"segment_id"`
+		PassengerID string               `json:"passenger_id"`
+		Seat        string               `json:"seat"`
+		SeatType    string               `json:"seat_type"`
+		ProductInfo []AirlineProductInfo `json:"product_info,omitempty"`
+	}
+
+	AirlineProductInfo struct {
+		Title string `json:"title"`
+		Value string `json:"value"`
+	}
+
+	AirlinePriceInfo struct {
+		Title    string `json:"title"`
+		Amount   string `json:"amount"`
+		Currency string `json:"currency,omitempty"`
+	}
+)
+
+// Convert the template to an Attachment to send in a message.
+func (t AirlineBoardingPassTemplate) Attachment() Attachment {
+	return airlineAttachment(AttachmentPayload{
+		TemplateType: TemplateTypeAirlineBoardingPass,
+		IntroMessage: t.IntroMessage,
+		Locale:       t.Locale,
+		ThemeColor:   t.ThemeColor,
+		BoardingPass: t.BoardingPasses,
+	})
+}
+
+// Convert the template to an Attachment to send in a message.
+func (t AirlineItineraryTemplate) Attachment() Attachment {
+	return airlineAttachment(AttachmentPayload{
+		TemplateType:         TemplateTypeAirlineItinerary,
+		IntroMessage:         t.IntroMessage,
+		Locale:               t.Locale,
+		ThemeColor:           t.ThemeColor,
+		PNRNumber:            t.PNRNumber,
+		PassengerInfo:        t.PassengerInfo,
+		FlightInfo:           t.FlightInfo,
+		PassengerSegmentInfo: t.PassengerSegmentInfo,
+		PriceInfo:            t.PriceInfo,
+		BasePrice:            t.BasePrice,
+		Tax:                  t.Tax,
+		TotalPrice:           t.TotalPrice,
+		Currency:             t.Currency,
+	})
+}
+
+// Convert the template to an Attachment to send in a message.
+func (t AirlineCheckinTemplate) Attachment() Attachment {
+	return airlineAttachment(AttachmentPayload{
+		TemplateType: TemplateTypeAirlineCheckin,
+		IntroMessage: t.IntroMessage,
+		Locale:       t.Locale,
+		ThemeColor:   t.ThemeColor,
+		PNRNumber:    t.PNRNumber,
+		CheckinURL:   t.CheckinURL,
+		FlightInfo:   t.FlightInfo,
+	})
+}
+
+// Convert the template to an Attachment to send in a message.
+func (t AirlineUpdateTemplate) Attachment() Attachment {
+	flightInfo := t.UpdateFlightInfo
+	return airlineAttachment(AttachmentPayload{
+		TemplateType:     TemplateTypeAirlineUpdate,
+		IntroMessage:     t.IntroMessage,
+		Locale:           t.Locale,
+		ThemeColor:       t.ThemeColor,
+		UpdateType:       t.UpdateType,
+		PNRNumber:        t.PNRNumber,
+		UpdateFlightInfo: &flightInfo,
+	})
+}
+
+// Check the template against the rules of the boarding pass template.
+//
+// Output:
+// 		ValidationErrors if the template is invalid, nil otherwise
+func (t AirlineBoardingPassTemplate) Validate() error {
+	var errs ValidationErrors
+	errs.validateAirlineHeader(t.IntroMessage, t.Locale, t.ThemeColor)
+	if len(t.BoardingPasses) == 0 {
+		errs.add("boarding_pass", "is required")
+	}
+	for i, boardingPass := range t.BoardingPasses {
+		field := indexField("boarding_pass", i)
+		errs.required(field+".passenger_name", boardingPass.PassengerName)
+		errs.required(field+".pnr_number", boardingPass.PNRNumber)
+		errs.required(field+".logo_image_url", boardingPass.LogoImageURL)
+		errs.required(field+".above_bar_code_image_url", boardingPass.AboveBarcodeImageURL)
+		if (boardingPass.QRCode == "") == (boardingPass.BarcodeImageURL == "") {
+			errs.add(field, "exactly one of qr_code and barcode_image_url must be set")
+		}
+		errs.maxItems(field+".auxiliary_fields", len(boardingPass.AuxiliaryFields), airlineMaxAuxiliaryFields)
+		errs.maxItems(field+".secondary_fields", len(boardingPass.SecondaryFields), airlineMaxSecondaryFields)
+		errs.validateFlightInfo(field+".flight_info", boardingPass.FlightInfo, false)
+	}
+	return errs.err()
+}
+
+// Check the template against the rules of the itinerary template.
+//
+// Output:
+// 		ValidationErrors if the template is invalid, nil otherwise
+func (t AirlineItineraryTemplate) Validate() error {
+	var errs ValidationErrors
+	errs.validateAirlineHeader(t.IntroMessage, t.Locale, t.ThemeColor)
+	errs.required("pnr_number", t.PNRNumber)
+	errs.required("total_price", t.TotalPrice)
+	errs.required("currency", t.Currency)
+
+	if len(t.PassengerInfo) == 0 {
+		errs.add("passenger_info", "is required")
+	}
+	for i, passenger := range t.PassengerInfo {
+		field := indexField("passenger_info", i)
+		errs.required(field+".passenger_id", passenger.PassengerID)
+		errs.required(field+".name", passenger.Name)
+	}
+
+	if len(t.FlightInfo) == 0 {
+		errs.add("flight_info", "is required")
+	}
+	for i, flightInfo := range t.FlightInfo {
+		errs.validateFlightInfo(indexField("flight_info", i), flightInfo, true)
+	}
+
+	if len(t.PassengerSegmentInfo) == 0 {
+		errs.add("passenger_segment_info", "is required")
+	}
+	for i, segment := range t.PassengerSegmentInfo {
+		field := indexField("passenger_segment_info", i)
+		errs.required(field+".segment_id", segment.SegmentID)
+		errs.required(field+".passenger_id", segment.PassengerID)
+		errs.required(field+".seat", segment.Seat)
+		errs.required(field+".seat_type", segment.SeatType)
+		errs.maxItems(field+".product_info", len(segment.ProductInfo), airlineMaxProductInfo)
+	}
+
+	errs.maxItems("price_info", len(t.PriceInfo), airlineMaxPriceInfo)
+	for i, price := range t.PriceInfo {
+		field := indexField("price_info", i)
+		errs.required(field+".title", price.Title)
+		errs.required(field+".amount", price.Amount)
+	}
+	return errs.err()
+}
+
+// Check the template against the rules of the check-in template.
+//
+// Output:
+// 		ValidationErrors if the template is invalid, nil otherwise
+func (t AirlineCheckinTemplate) Validate() error {
+	var errs ValidationErrors
+	errs.validateAirlineHeader(t.IntroMessage, t.Locale, t.ThemeColor)
+	errs.required("pnr_number", t.PNRNumber)
+	errs.required("checkin_url", t.CheckinURL)
+	if len(t.FlightInfo) == 0 {
+		errs.add("flight_info", "is required")
+	}
+	for i, flightInfo := range t.FlightInfo {
+		errs.validateFlightInfo(indexField("flight_info", i), flightInfo, false)
+	}
+	return errs.err()
+}
+
+// Check the template against the rules of the flight update template.
+//
+// Output:
+// 		ValidationErrors if the template is invalid, nil otherwise
+func (t AirlineUpdateTemplate) Validate() error {
+	var errs ValidationErrors
+	errs.validateAirlineHeader(t.IntroMessage, t.Locale, t.ThemeColor)
+	errs.required("pnr_number", t.PNRNumber)
+	switch t.UpdateType {
+	case AirlineUpdateTypeDelay, AirlineUpdateTypeGateChange, AirlineUpdateTypeCancellation:
+	case "":
+		errs.add("update_type", "is required")
+	default:
+		errs.add("update_type", "must be delay, gate_change or cancellation")
+	}
+	errs.validateFlightInfo("update_flight_info", t.UpdateFlightInfo, false)
+	return errs.err()
+}
+
+// Send boarding passes to the specified recipient, the template is validated before it is sent.
+// https://developers.facebook.com/docs/messenger-platform/reference/templates/airline-boarding-pass
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		recipientID: recipient id to send to
+// 		template: an AirlineBoardingPassTemplate object
+// 		options: optional settings of the send, see SendOption
+// Output:
+// 		Response from API and an error if exists, ValidationErrors if the template is invalid
+func (bot *Bot) SendAirlineBoardingPass(ctx context.Context, recipientID string, template AirlineBoardingPassTemplate, options ...SendOption) (*SendResponse, error) {
+	if err := template.Validate(); err != nil {
+		return nil, err
+	}
+	return bot.SendAttachmentMessage(ctx, recipientID, template.Attachment(), options...)
+}
+
+// Send an itinerary to the specified recipient, the template is validated before it is sent.
+// https://developers.facebook.com/docs/messenger-platform/reference/templates/airline-itinerary
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		recipientID: recipient id to send to
+// 		template: an AirlineItineraryTemplate object
+// 		options: optional settings of the send, see SendOption
+// Output:
+// 		Response from API and an error if exists, ValidationErrors if the template is invalid
+func (bot *Bot) SendAirlineItinerary(ctx context.Context, recipientID string, template AirlineItineraryTemplate, options ...SendOption) (*SendResponse, error) {
+	if err := template.Validate(); err != nil {
+		return nil, err
+	}
+	return bot.SendAttachmentMessage(ctx, recipientID, template.Attachment(), options...)
+}
+
+// Send a check-in reminder to the specified recipient, the template is validated before it is sent.
+// https://developers.facebook.com/docs/messenger-platform/reference/templates/airline-check-in
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		recipientID: recipient id to send to
+// 		template: an AirlineCheckinTemplate object
+// 		options: optional settings of the send, see SendOption
+// Output:
+// 		Response from API and an error if exists, ValidationErrors if the template is invalid
+func (bot *Bot) SendAirlineCheckin(ctx context.Context, recipientID string, template AirlineCheckinTemplate, options ...SendOption) (*SendResponse, error) {
+	if err := template.Validate(); err != nil {
+		return nil, err
+	}
+	return bot.SendAttachmentMessage(ctx, recipientID, template.Attachment(), options...)
+}
+
+// Send a flight update to the specified recipient, the template is validated before it is sent.
+// https://developers.facebook.com/docs/messenger-platform/reference/templates/airline-flight-update
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		recipientID: recipient id to send to
+// 		template: an AirlineUpdateTemplate object
+// 		options: optional settings of the send, see SendOption
+// Output:
+// 		Response from API and an error if exists, ValidationErrors if the template is invalid
+func (bot *Bot) SendAirlineUpdate(ctx context.Context, recipientID string, template AirlineUpdateTemplate, options ...SendOption) (*SendResponse, error) {
+	if err := template.Validate(); err != nil {
+		return nil, err
+	}
+	return bot.SendAttachmentMessage(ctx, recipientID, template.Attachment(), options...)
+}
+
+func airlineAttachment(payload AttachmentPayload) Attachment {
+	return Attachment{Type: AttachmentTypeTemplate, Payload: payload}
+}
+
+// Check the fields shared by all airline templates
+func (errs *ValidationErrors) validateAirlineHeader(introMessage string, locale string, themeColor string) {
+	errs.required("intro_message", introMessage)
+	errs.required("locale", locale)
+	if themeColor != "" && !themeColorPattern.MatchString(themeColor) {
+		errs.add("theme_color", "must be a hex color, e.g. #009ddc")
+	}
+}
+
+// Check a flight info, itinerary flights must also identify their connection and segment
+func (errs *ValidationErrors) validateFlightInfo(field string, flightInfo AirlineFlightInfo, itinerary bool) {
+	if itinerary {
+		errs.required(field+".connection_id", flightInfo.ConnectionID)
+		errs.required(field+".segment_id", flightInfo.SegmentID)
+	}
+	errs.required(field+".flight_number", flightInfo.FlightNumber)
+	errs.required(field+".departure_airport.airport_code", flightInfo.DepartureAirport.AirportCode)
+	errs.required(field+".departure_airport.city", flightInfo.DepartureAirport.City)
+	errs.required(field+".arrival_airport.airport_code", flightInfo.ArrivalAirport.AirportCode)
+	errs.required(field+".arrival_airport.city", flightInfo.ArrivalAirport.City)
+	errs.required(field+".flight_schedule.departure_time", flightInfo.FlightSchedule.DepartureTime)
+}
@@ -0,0 +1,246 @@
+package messenger
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// AttachmentStore persists the mapping from a cache key to a reusable attachment id.
+// Implementations must be safe for concurrent use.
+type AttachmentStore interface {
+	// Get returns the attachment id stored for key, ok is false when there is none
+	Get(key string) (attachmentID string, ok bool, err error)
+	// Set stores the attachment id for key
+	Set(key string, attachmentID string) error
+	// Delete forgets key, e.g. when Facebook does not know the attachment id anymore
+	Delete(key string) error
+}
+
+// AttachmentCache maps attachment urls and contents to reusable attachment ids, so Facebook
+// does not fetch or receive the same asset again on every send.
+// Install it on a Bot with WithAttachmentCache, then SendAttachmentUrl, SendImageUrl and friends,
+// SendAttachmentReader, SendAttachmentFile and the upload methods use the cached ids transparently.
+type AttachmentCache struct {
+	store AttachmentStore
+}
+
+// Create a new AttachmentCache instance.
+//
+// Input:
+// 		store: where the attachment ids are kept, e.g. NewMemoryAttachmentStore() or NewFileAttachmentStore(path)
+// Output:
+// 		An AttachmentCache instance
+func NewAttachmentCache(store AttachmentStore) *AttachmentCache {
+	return &AttachmentCache{store: store}
+}
+
+// Send attachments of the Bot by their cached attachment ids, see AttachmentCache.
+//
+// Input:
+// 		cache: the AttachmentCache to use, can be shared by several bots of the same page
+func WithAttachmentCache(cache *AttachmentCache) BotOption {
+	return func(bot *Bot) {
+		bot.attachmentCache = cache
+	}
+}
+
+// Return the cache key of an attachment url
+func attachmentUrlKey(attachmentType AttachmentType, attachmentUrl string) string {
+	return "url:" + string(attachmentType) + ":" + attachmentUrl
+}
+
+// Return the cache key of an attachment content
+func attachmentContentKey(attachmentType AttachmentType, data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + string(attachmentType) + ":" + hex.EncodeToString(sum[:])
+}
+
+// Report whether Facebook rejected a cached attachment id, so it has to be uploaded again.
+// Other invalid parameters, e.g. a bad tag or quick reply, would fail the upload as well and keep the id
+func isStaleAttachment(err error) bool {
+	var graphErr *GraphError
+	if !errors.As(err, &graphErr) || graphErr.Code != ErrorCodeInvalidParameter || graphErr.IsUserUnavailable() {
+		return false
+	}
+	message := strings.ToLower(graphErr.Message)
+	return strings.Contains(message, "attachment_id") || strings.Contains(message, "attachment id")
+}
+
+// Send an attachment by the id cached for key, or with send when there is none and cache the returned id
+func (c *AttachmentCache) send(ctx context.Context, bot *Bot, recipientID string, attachmentType AttachmentType, key string,
+	options []SendOption, send func() (*SendResponse, error)) (*SendResponse, error) {
+	attachmentID, ok, err := c.store.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		resp, err := bot.SendAttachmentID(ctx, recipientID, attachmentType, attachmentID, options...)
+		if !isStaleAttachment(err) {
+			return resp, err
+		}
+		if err := c.store.Delete(key); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := send()
+	if err != nil {
+		return nil, err
+	}
+	c.remember(key, resp.AttachmentID)
+	return resp, nil
+}
+
+// Return the attachment id cached for key, or upload it with upload and cache the returned id
+func (c *AttachmentCache) upload(key string, upload func() (string, error)) (string, error) {
+	attachmentID, ok, err := c.store.Get(key)
+	if err != nil || ok {
+		return attachmentID, err
+	}
+
+	attachmentID, err = upload()
+	if err != nil {
+		return "", err
+	}
+	c.remember(key, attachmentID)
+	return attachmentID, nil
+}
+
+// Store an attachment id. The message is already sent at this point, so a failing store is only logged
+func (c *AttachmentCache) remember(key string, attachmentID string) {
+	if attachmentID == "" {
+		return
+	}
+	if err := c.store.Set(key, attachmentID); err != nil {
+		log.Println("messenger: can not cache attachment id:", err.Error())
+	}
+}
+
+// Read the whole content of r, so it can be hashed and still be uploaded
+func readAttachment(r io.Reader) ([]byte, io.Reader, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, bytes.NewReader(data), nil
+}
+
+// MemoryAttachmentStore is an AttachmentStore which keeps the attachment ids in memory.
+type MemoryAttachmentStore struct {
+	mu  sync.RWMutex
+	ids map[string]string
+}
+
+// Create a new, empty MemoryAttachmentStore instance.
+func NewMemoryAttachmentStore() *MemoryAttachmentStore {
+	return &MemoryAttachmentStore{ids: make(map[string]string)}
+}
+
+func (s *MemoryAttachmentStore) Get(key string) (string, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	attachmentID, ok := s.ids[key]
+	return attachmentID, ok, nil
+}
+
+func (s *MemoryAttachmentStore) Set(key string, attachmentID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ids[key] = attachmentID
+	return nil
+}
+
+func (s *MemoryAttachmentStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.ids, key)
+	return nil
+}
+
+// FileAttachmentStore is an AttachmentStore which keeps the attachment ids in memory
+// and writes them to a JSON file on every change, so they survive a restart.
+type FileAttachmentStore struct {
+	path string
+
+	mu  sync.RWMutex
+	ids map[string]string
+}
+
+// Create a new FileAttachmentStore instance, loading the ids saved in the file if it exists.
+//
+// Input:
+// 		path: path of the JSON file
+// Output:
+// 		A FileAttachmentStore instance and an error if the file exists but can not be read
+func NewFileAttachmentStore(path string) (*FileAttachmentStore, error) {
+	s := &FileAttachmentStore{path: path, ids: make(map[string]string)}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &s.ids); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (s *FileAttachmentStore) Get(key string) (string, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	attachmentID, ok := s.ids[key]
+	return attachmentID, ok, nil
+}
+
+func (s *FileAttachmentStore) Set(key string, attachmentID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ids[key] = attachmentID
+	return s.save()
+}
+
+func (s *FileAttachmentStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.ids, key)
+	return s.save()
+}
+
+// Write all ids to a temporary file and move it over the store file, the caller must hold s.mu
+func (s *FileAttachmentStore) save() error {
+	data, err := json.MarshalIndent(s.ids, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -0,0 +1,31 @@
+package messenger
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsStaleAttachment(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		stale bool
+	}{
+		{"invalid attachment id", &GraphError{Code: ErrorCodeInvalidParameter, Message: "(#100) Invalid attachment_id"}, true},
+		{"attachment id of another page", &GraphError{Code: ErrorCodeInvalidParameter, Message: "(#100) Param attachment_id must be a valid attachment ID"}, true},
+		{"invalid tag", &GraphError{Code: ErrorCodeInvalidParameter, Message: "(#100) Param tag must be one of {CONFIRMED_EVENT_UPDATE, POST_PURCHASE_UPDATE, ACCOUNT_UPDATE, HUMAN_AGENT}"}, false},
+		{"invalid quick reply", &GraphError{Code: ErrorCodeInvalidParameter, Message: "(#100) Invalid keys \"foo\" were found in param \"message[quick_replies][0]\""}, false},
+		{"no matching user", &GraphError{Code: ErrorCodeInvalidParameter, ErrorSubcode: ErrorSubcodeNoMatchingUser, Message: "(#100) No matching user found"}, false},
+		{"server error", &GraphError{Code: ErrorCodeUnknown, Message: "An unknown error occurred"}, false},
+		{"network error", errors.New("connection reset"), false},
+		{"no error", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if stale := isStaleAttachment(tt.err); stale != tt.stale {
+				t.Errorf("isStaleAttachment = %v, want %v", stale, tt.stale)
+			}
+		})
+	}
+}
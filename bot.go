@@ -3,16 +3,21 @@ package messenger
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"log"
+	"errors"
+	"io"
+	"io/ioutil"
 	"net/http"
-	"strconv"
+	"net/url"
 	"time"
 )
 
 type (
 	NotificationType string
 	SenderAction     string
+	MessagingType    string
+	MessageTag       string
 )
 
 const (
@@ -28,21 +33,62 @@ const (
 	SenderActionMarkSeen  = SenderAction("mark_seen")
 	SenderActionTypingOn  = SenderAction("typing_on")
 	SenderActionTypingOff = SenderAction("typing_off")
+
+	MessagingTypeResponse   = MessagingType("RESPONSE")
+	MessagingTypeUpdate     = MessagingType("UPDATE")
+	MessagingTypeMessageTag = MessagingType("MESSAGE_TAG")
+
+	MessageTagConfirmedEventUpdate = MessageTag("CONFIRMED_EVENT_UPDATE")
+	MessageTagPostPurchaseUpdate   = MessageTag("POST_PURCHASE_UPDATE")
+	MessageTagAccountUpdate        = MessageTag("ACCOUNT_UPDATE")
+	MessageTagHumanAgent           = MessageTag("HUMAN_AGENT")
 )
 
 type (
 	Payload struct {
-		Recipient        *Recipient       `json:"recipient,omitempty"`
-		GetStarted       *GetStarted      `json:"get_started,omitempty"`
-		PersistentMenu   []PersistentMenu `json:"persistent_menu,omitempty"`
-		NotificationType NotificationType `json:"notification_type,omitempty"`
-		SenderAction     SenderAction     `json:"sender_action,omitempty"`
-		DeletedFields    []string         `json:"fields,omitempty"`
-		Message          *Message         `json:"message,omitempty"`
+		Recipient          *Recipient       `json:"recipient,omitempty"`
+		GetStarted         *GetStarted      `json:"get_started,omitempty"`
+		PersistentMenu     []PersistentMenu `json:"persistent_menu,omitempty"`
+		Greeting           []Greeting       `json:"greeting,omitempty"`
+		IceBreakers        []IceBreaker     `json:"ice_breakers,omitempty"`
+		WhitelistedDomains []string         `json:"whitelisted_domains,omitempty"`
+		AccountLinkingURL  string           `json:"account_linking_url,omitempty"`
+		HomeURL            *HomeURL         `json:"home_url,omitempty"`
+		MessagingType      MessagingType    `json:"messaging_type,omitempty"`
+		Tag                MessageTag       `json:"tag,omitempty"`
+		NotificationType   NotificationType `json:"notification_type,omitempty"`
+		SenderAction       SenderAction     `json:"sender_action,omitempty"`
+		DeletedFields      []string         `json:"fields,omitempty"`
+		Message            *Message         `json:"message,omitempty"`
+		PersonaID          string           `json:"persona_id,omitempty"`
 	}
 
+	// Recipient identifies who receives a message, exactly one of its identifiers must be set.
+	// Name can only be given with PhoneNumber.
+	// https://developers.facebook.com/docs/messenger-platform/reference/send-api/#recipient
 	Recipient struct {
-		ID string `json:"id"`
+		ID                        string         `json:"id,omitempty"`
+		UserRef                   string         `json:"user_ref,omitempty"`
+		PhoneNumber               string         `json:"phone_number,omitempty"`
+		Name                      *RecipientName `json:"name,omitempty"`
+		PostID                    string         `json:"post_id,omitempty"`
+		CommentID                 string         `json:"comment_id,omitempty"`
+		OneTimeNotifToken         string         `json:"one_time_notif_token,omitempty"`
+		NotificationMessagesToken string         `json:"notification_messages_token,omitempty"`
+	}
+
+	// RecipientName is matched with the phone number of a recipient by customer matching.
+	RecipientName struct {
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+	}
+
+	// SendResponse is returned by Send API for a successful request, MessageID can be matched with
+	// the mids of later delivery, read and reaction webhook events.
+	SendResponse struct {
+		RecipientID  string `json:"recipient_id"`
+		MessageID    string `json:"message_id,omitempty"`
+		AttachmentID string `json:"attachment_id,omitempty"`
 	}
 
 	GetStarted struct {
@@ -60,57 +106,151 @@ type Bot struct {
 	AccessToken string
 	ApiVersion  string
 	GraphUrl    string
+
+	client      *http.Client
+	retryPolicy *RetryPolicy
+	rateLimiter *RateLimiter
+
+	attachmentCache  *AttachmentCache
+	userProfileCache *UserProfileCache
+	windowGuard      *windowGuard
+	validate         bool
 }
 
 // Create a new Bot instance with your page access token, and an api version.
+// The Bot keeps one http.Client for all of its requests, so connections to Graph API are reused.
 //
 // Input:
 // 		accessToken: your page access token
 //		apiVersion: specified api version, use DefaultAPIVersion if you want to use default api version
+// 		options: optional settings of the Bot, see BotOption
 // Output:
 // 		A Bot instance
-func NewBot(accessToken string, apiVersion string) *Bot {
+func NewBot(accessToken string, apiVersion string, options ...BotOption) *Bot {
 	if apiVersion == "" {
 		apiVersion = DefaultApiVersion
 	}
-	return &Bot{
+	bot := &Bot{
 		AccessToken: accessToken,
 		ApiVersion:  apiVersion,
 		GraphUrl:    kGraphUrl + apiVersion,
+		client:      &http.Client{Timeout: kDefaultTimeout},
+	}
+	for _, option := range options {
+		option(bot)
+	}
+	return bot
+}
+
+// Return the http client of the Bot, fall back to a shared default client
+// when the Bot was not created with NewBot
+func (bot *Bot) httpClient() *http.Client {
+	if bot.client != nil {
+		return bot.client
 	}
+	return defaultHTTPClient
 }
 
 // Send raw message with a sub path, a httpMethod, and a payload object
 // This method can not be used outside the package
 //
 // Input:
+// 		ctx: context of the request, cancelling it aborts the call
 // 		requestSubPath: sub path of endpoint
 // 		method: http method of this request
-// 		payload: a Payload object to send
+// 		payload: the request body, a Payload object or a request struct of the endpoint, encoded as JSON
+// 		result: pointer to a value which the response body is decoded into, can be nil
 // Output:
-// 		Response from API and an error if exists
-func (bot *Bot) sendRaw(requestSubPath string, method string, payload Payload) (*http.Response, error) {
-	//fmt.Println("--------------------")
-	//defer fmt.Println("--------------------")
-	// Create request endpoint with given sub path
-	requestEndpoint := bot.GraphUrl + requestSubPath
-
-	client := &http.Client{
-		Timeout: time.Second * 10,
+// 		An error if exists, a *GraphError if Graph API rejected the request
+func (bot *Bot) sendRaw(ctx context.Context, requestSubPath string, method string, payload interface{}, result interface{}) error {
+	// Encode the payload into request body
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
 	}
 
-	//jsonPayload, _ := json.MarshalIndent(payload, "", "  ")
-	//fmt.Println(string(jsonPayload))
+	return bot.do(ctx, method, requestSubPath, "application/json", body, nil, result)
+}
 
-	// Encode the payload into request body
-	body := new(bytes.Buffer)
-	if err := json.NewEncoder(body).Encode(payload); err != nil {
-		log.Println(err.Error())
-		return nil, err
+// Get a Graph API object with a sub path and query params
+// This method can not be used outside the package
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		requestSubPath: sub path of endpoint
+// 		query: query params of the request, can be nil
+// 		result: pointer to a value which the response body is decoded into
+// Output:
+// 		An error if exists, a *GraphError if Graph API rejected the request
+func (bot *Bot) get(ctx context.Context, requestSubPath string, query url.Values, result interface{}) error {
+	if len(query) > 0 {
+		requestSubPath += "?" + query.Encode()
 	}
+	return bot.do(ctx, http.MethodGet, requestSubPath, "", nil, nil, result)
+}
+
+// Send a request to Graph API, retrying transient failures according to the retry policy of the Bot
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call and any pending retry
+// 		method: http method of this request
+// 		requestSubPath: sub path of endpoint
+// 		contentType: content type of the body
+// 		body: encoded request body, sent again as is on every attempt
+// 		recipient: recipient of a Send API call, every retry of the call waits for the rate limiter,
+// 		nil for other calls
+// 		result: pointer to a value which the response body is decoded into, can be nil
+// Output:
+// 		An error if exists, a *GraphError if Graph API rejected the request. When the rate limiter
+// 		refuses a retry, the error of the last attempt is returned
+func (bot *Bot) do(ctx context.Context, method string, requestSubPath string, contentType string, body []byte,
+	recipient *Recipient, result interface{}) error {
+	policy := bot.retryPolicy
+	for attempt := 1; ; attempt++ {
+		err := bot.doOnce(ctx, method, requestSubPath, contentType, body, result)
+		if err == nil || ctx.Err() != nil || policy == nil ||
+			attempt >= policy.MaxAttempts || !isRetryable(err) {
+			return err
+		}
+
+		delay := policy.backoff(attempt)
+		var graphErr *GraphError
+		if errors.As(err, &graphErr) && graphErr.RetryAfter > delay {
+			delay = graphErr.RetryAfter
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		if recipient != nil {
+			if bot.waitRateLimit(ctx, recipient) != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Send a single request to Graph API
+func (bot *Bot) doOnce(ctx context.Context, method string, requestSubPath string, contentType string, body []byte, result interface{}) error {
+	// Create request endpoint with given sub path
+	requestEndpoint := bot.GraphUrl + requestSubPath
 
-	req, _ := http.NewRequest(method, requestEndpoint, body)
-	req.Header.Add("Content-Type", "application/json")
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, requestEndpoint, reqBody)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Add("Content-Type", contentType)
+	}
 
 	// Add access token to request params
 	q := req.URL.Query()
@@ -118,156 +258,248 @@ func (bot *Bot) sendRaw(requestSubPath string, method string, payload Payload) (
 	req.URL.RawQuery = q.Encode()
 
 	// Start the request
-	resp, err := client.Do(req)
+	resp, err := bot.httpClient().Do(req)
 	if err != nil {
-		log.Println("Error request:", err.Error())
-		return resp, err
+		return err
 	}
 
 	defer resp.Body.Close()
 
-	if err := json.NewEncoder(body).Encode(resp.Body); err != nil {
-		log.Println(err.Error())
-		return resp, err
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
 	}
 
-	if resp.StatusCode != 200 {
-		log.Println("Error http response -> %v", resp)
-		log.Println("Error http " + strconv.Itoa(resp.StatusCode) + " -> " + body.String())
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		graphErr := newGraphError(resp.StatusCode, respBody)
+		graphErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		return graphErr
 	}
 
-	return resp, err
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(respBody, result)
 }
 
 // Send raw message with a payload instance
 // https://developers.facebook.com/docs/messenger-platform/reference/send-api/
 //
 // Input:
+// 		ctx: context of the request, cancelling it aborts the call
 // 		payload: a Payload object to send
 // Output:
 // 		Response from API and an error if exists
-func (bot *Bot) SendRawMessage(payload Payload) (*http.Response, error) {
-	return bot.sendRaw("/me/messages", http.MethodPost, payload)
+func (bot *Bot) SendRawMessage(ctx context.Context, payload Payload) (*SendResponse, error) {
+	if err := bot.prepareSend(ctx, &payload); err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp SendResponse
+	if err := bot.do(ctx, http.MethodPost, "/me/messages", "application/json", body, payload.Recipient, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Complete a payload of Send API and wait for the rate limiter before it is sent
+// This method can not be used outside the package
+//
+// Input:
+// 		ctx: context of the send
+// 		payload: the Payload to send, messages without messaging type are sent as RESPONSE,
+// 		except messages to a notification token
+// Output:
+// 		An error if the payload must not be sent
+func (bot *Bot) prepareSend(ctx context.Context, payload *Payload) error {
+	if payload.Recipient != nil {
+		if err := payload.Recipient.Validate(); err != nil {
+			return err
+		}
+	}
+	if bot.validate && payload.Message != nil {
+		var errs ValidationErrors
+		payload.Message.validate(&errs, "message")
+		if err := errs.err(); err != nil {
+			return err
+		}
+	}
+
+	if payload.Message != nil && payload.MessagingType == "" && payload.Recipient != nil &&
+		payload.Recipient.OneTimeNotifToken == "" && payload.Recipient.NotificationMessagesToken == "" {
+		if payload.Tag != "" {
+			payload.MessagingType = MessagingTypeMessageTag
+		} else {
+			payload.MessagingType = MessagingTypeResponse
+		}
+	}
+
+	if bot.windowGuard != nil {
+		if err := bot.windowGuard.check(payload); err != nil {
+			return err
+		}
+	}
+
+	return bot.waitRateLimit(ctx, payload.Recipient)
 }
 
 // Send message to a recipient with recipientID
 // https://developers.facebook.com/docs/messenger-platform/reference/send-api/
 //
 // Input:
+// 		ctx: context of the request, cancelling it aborts the call
 // 		recipientID: recipient id to send to
 // 		payload: a Payload object to send
 // 		notificationType: type of notification, see NotificationType
 // Output:
 // 		Response from API and an error if exists
-func (bot *Bot) SendRecipient(recipientID string, payload Payload, notificationType NotificationType) (*http.Response, error) {
+func (bot *Bot) SendRecipient(ctx context.Context, recipientID string, payload Payload, notificationType NotificationType) (*SendResponse, error) {
 	payload.Recipient = &Recipient{ID: recipientID}
 	payload.NotificationType = notificationType
-	return bot.SendRawMessage(payload)
+	return bot.SendRawMessage(ctx, payload)
 }
 
 // Send typing indicators or send read receipts to the specified recipient.
 // https://developers.facebook.com/docs/messenger-platform/send-api-reference/sender-actions
 //
 // Input:
+// 		ctx: context of the request, cancelling it aborts the call
 // 		recipientID: recipient id to send to
 // 		action: action type (mark_seen, typing_on, typing_off)
+// 		options: optional settings of the send, see SendOption
 // Output:
 // 		Response from API and an error if exists
-func (bot *Bot) SendAction(recipientID string, action SenderAction, notificationType NotificationType) (*http.Response, error) {
-	payload := Payload{SenderAction: action}
-	return bot.SendRecipient(recipientID, payload, notificationType)
+func (bot *Bot) SendAction(ctx context.Context, recipientID string, action SenderAction, notificationType NotificationType, options ...SendOption) (*SendResponse, error) {
+	payload := Payload{
+		Recipient:        &Recipient{ID: recipientID},
+		SenderAction:     action,
+		NotificationType: notificationType,
+	}
+	for _, option := range options {
+		option(&payload)
+	}
+	return bot.SendRawMessage(ctx, payload)
 }
 
 // Send message to the specified recipient.
 // https://developers.facebook.com/docs/messenger-platform/send-messages
 //
 // Input:
+// 		ctx: context of the request, cancelling it aborts the call
 // 		recipientID: recipient id to send to
 // 		message: a Message object
+// 		options: optional settings of the send, see SendOption
 // Output:
 // 		Response from API and an error if exists
-func (bot *Bot) SendMessage(recipientID string, message Message) (*http.Response, error) {
+func (bot *Bot) SendMessage(ctx context.Context, recipientID string, message Message, options ...SendOption) (*SendResponse, error) {
 	payload := Payload{
 		Recipient: &Recipient{ID: recipientID},
 		Message:   &message,
 	}
-	return bot.SendRawMessage(payload)
+	for _, option := range options {
+		option(&payload)
+	}
+	return bot.SendRawMessage(ctx, payload)
 }
 
 // Send text message to the specified recipient.
 // https://developers.facebook.com/docs/messenger-platform/send-messages#sending_text
 //
 // Input:
+// 		ctx: context of the request, cancelling it aborts the call
 // 		recipientID: recipient id to send to
 // 		text: a text message
+// 		options: optional settings of the send, see SendOption
 // Output:
 // 		Response from API and an error if exists
-func (bot *Bot) SendTextMessage(recipientID string, text string) (*http.Response, error) {
+func (bot *Bot) SendTextMessage(ctx context.Context, recipientID string, text string, options ...SendOption) (*SendResponse, error) {
 	message := Message{
 		Text: text,
 	}
-	return bot.SendMessage(recipientID, message)
+	return bot.SendMessage(ctx, recipientID, message, options...)
 }
 
 // Send quick replies to the specified recipient.
 // https://developers.facebook.com/docs/messenger-platform/send-messages/quick-replies
 //
 // Input:
+// 		ctx: context of the request, cancelling it aborts the call
 // 		recipientID: recipient id to send to
 // 		text: title of message
 // 		quickReplies: an array of QuickReply objects, up to 13 elements
+// 		options: optional settings of the send, see SendOption
 // Output:
 // 		Response from API and an error if exists
-func (bot *Bot) SendQuickReplies(recipientID string, text string, attachment *Attachment, quickReplies []QuickReply) (*http.Response, error) {
+func (bot *Bot) SendQuickReplies(ctx context.Context, recipientID string, text string, attachment *Attachment, quickReplies []QuickReply, options ...SendOption) (*SendResponse, error) {
 	message := Message{
 		Text:         text,
 		Attachment:   attachment,
 		QuickReplies: quickReplies,
 	}
-	return bot.SendMessage(recipientID, message)
+	return bot.SendMessage(ctx, recipientID, message, options...)
 }
 
 // Send attachment message to the specified recipient.
 // https://developers.facebook.com/docs/messenger-platform/send-messages#sending_attachments
 //
 // Input:
+// 		ctx: context of the request, cancelling it aborts the call
 // 		recipientID: recipient id to send to
 // 		attachment: an attachment
+// 		options: optional settings of the send, see SendOption
 // Output:
 // 		Response from API and an error if exists
-func (bot *Bot) SendAttachmentMessage(recipientID string, attachment Attachment) (*http.Response, error) {
+func (bot *Bot) SendAttachmentMessage(ctx context.Context, recipientID string, attachment Attachment, options ...SendOption) (*SendResponse, error) {
 	message := Message{
 		Attachment: &attachment,
 	}
-	return bot.SendMessage(recipientID, message)
+	return bot.SendMessage(ctx, recipientID, message, options...)
 }
 
 // Send attachment message to the specified recipient using URL.
+// With an AttachmentCache installed, the attachment is sent by its cached attachment id.
 // https://developers.facebook.com/docs/messenger-platform/send-messages#sending_attachments
 //
 // Input:
+// 		ctx: context of the request, cancelling it aborts the call
 // 		recipientID: recipient id to send to
 // 		attachmentType: type of the attachment
 // 		attachmentUrl: url of the attachment
+// 		options: optional settings of the send, see SendOption
 // Output:
 // 		Response from API and an error if exists
-func (bot *Bot) SendAttachmentUrl(recipientID string, attachmentType AttachmentType, attachmentUrl string) (*http.Response, error) {
+func (bot *Bot) SendAttachmentUrl(ctx context.Context, recipientID string, attachmentType AttachmentType, attachmentUrl string, options ...SendOption) (*SendResponse, error) {
 	attachment := Attachment{
 		Type:    attachmentType,
 		Payload: AttachmentPayload{URL: attachmentUrl},
 	}
-	return bot.SendAttachmentMessage(recipientID, attachment)
+	if bot.attachmentCache == nil || attachmentType == AttachmentTypeTemplate {
+		return bot.SendAttachmentMessage(ctx, recipientID, attachment, options...)
+	}
+
+	// Send by the cached attachment id, or ask Facebook for a reusable one
+	attachment.Payload.IsReusable = true
+	key := attachmentUrlKey(attachmentType, attachmentUrl)
+	return bot.attachmentCache.send(ctx, bot, recipientID, attachmentType, key, options, func() (*SendResponse, error) {
+		return bot.SendAttachmentMessage(ctx, recipientID, attachment, options...)
+	})
 }
 
 // Send generic message to the specified recipient.
 // https://developers.facebook.com/docs/messenger-platform/reference/template/generic
 //
 // Input:
+// 		ctx: context of the request, cancelling it aborts the call
 // 		recipientID: recipient id to send to
-// 		elements: an array of Element objects, can up to 10 elements
+// 		elements: an array of Element objects, can up to 10 elements, see Carousel for more
+// 		options: optional settings of the send, see SendOption
 // Output:
 // 		Response from API and an error if exists
-func (bot *Bot) SendGenericMessage(recipientID string, elements []Element) (*http.Response, error) {
+func (bot *Bot) SendGenericMessage(ctx context.Context, recipientID string, elements []Element, options ...SendOption) (*SendResponse, error) {
 	attachment := Attachment{
 		Type: AttachmentTypeTemplate,
 		Payload: AttachmentPayload{
@@ -275,19 +507,21 @@ func (bot *Bot) SendGenericMessage(recipientID string, elements []Element) (*htt
 			Elements:     elements,
 		},
 	}
-	return bot.SendAttachmentMessage(recipientID, attachment)
+	return bot.SendAttachmentMessage(ctx, recipientID, attachment, options...)
 }
 
 // Send button message to the specified recipient.
 // https://developers.facebook.com/docs/messenger-platform/send-messages/buttons
 //
 // Input:
+// 		ctx: context of the request, cancelling it aborts the call
 // 		recipientID: recipient id to send to
 // 		text: text of message to send
 // 		buttons: An array of Button objects, can up to 3 buttons
+// 		options: optional settings of the send, see SendOption
 // Output:
 // 		Response from API and an error if exists
-func (bot *Bot) SendButtonMessage(recipientID string, text string, buttons []Button) (*http.Response, error) {
+func (bot *Bot) SendButtonMessage(ctx context.Context, recipientID string, text string, buttons []Button, options ...SendOption) (*SendResponse, error) {
 	attachment := Attachment{
 		Type: AttachmentTypeTemplate,
 		Payload: AttachmentPayload{
@@ -296,95 +530,109 @@ func (bot *Bot) SendButtonMessage(recipientID string, text string, buttons []But
 			Buttons:      buttons,
 		},
 	}
-	return bot.SendAttachmentMessage(recipientID, attachment)
+	return bot.SendAttachmentMessage(ctx, recipientID, attachment, options...)
 }
 
 // Send an image message with image url
 // https://developers.facebook.com/docs/messenger-platform/send-messages#sending_attachments
 //
 // Input:
+// 		ctx: context of the request, cancelling it aborts the call
 // 		recipientID: recipient id to send to
 // 		imageUrl: url of the image that we want to send
+// 		options: optional settings of the send, see SendOption
 // Output:
 // 		Response from API and an error if exists
-func (bot *Bot) SendImageUrl(recipientID string, imageUrl string) (*http.Response, error) {
-	return bot.SendAttachmentUrl(recipientID, AttachmentTypeImage, imageUrl)
+func (bot *Bot) SendImageUrl(ctx context.Context, recipientID string, imageUrl string, options ...SendOption) (*SendResponse, error) {
+	return bot.SendAttachmentUrl(ctx, recipientID, AttachmentTypeImage, imageUrl, options...)
 }
 
 // Send an audio message with audio url
 // https://developers.facebook.com/docs/messenger-platform/send-messages#sending_attachments
 //
 // Input:
+// 		ctx: context of the request, cancelling it aborts the call
 // 		recipientID: recipient id to send to
 // 		imageUrl: url of the audio to send
+// 		options: optional settings of the send, see SendOption
 // Output:
 // 		Response from API and and error if exists
-func (bot *Bot) SendAudioUrl(recipientID string, audioUrl string) (*http.Response, error) {
-	return bot.SendAttachmentUrl(recipientID, AttachmentTypeAudio, audioUrl)
+func (bot *Bot) SendAudioUrl(ctx context.Context, recipientID string, audioUrl string, options ...SendOption) (*SendResponse, error) {
+	return bot.SendAttachmentUrl(ctx, recipientID, AttachmentTypeAudio, audioUrl, options...)
 }
 
 // Send a video message with video url
 // https://developers.facebook.com/docs/messenger-platform/send-messages#sending_attachments
 //
 // Input:
+// 		ctx: context of the request, cancelling it aborts the call
 // 		recipientID: recipient id to send to
 // 		videoUrl: url of the video to send
+// 		options: optional settings of the send, see SendOption
 // Output:
 // 		Response from API and an error if exists
-func (bot *Bot) SendVideoUrl(recipientID string, videoUrl string) (*http.Response, error) {
-	return bot.SendAttachmentUrl(recipientID, AttachmentTypeVideo, videoUrl)
+func (bot *Bot) SendVideoUrl(ctx context.Context, recipientID string, videoUrl string, options ...SendOption) (*SendResponse, error) {
+	return bot.SendAttachmentUrl(ctx, recipientID, AttachmentTypeVideo, videoUrl, options...)
 }
 
 // Send file with file url
 // https://developers.facebook.com/docs/messenger-platform/send-messages#sending_attachments
 //
 // Input:
+// 		ctx: context of the request, cancelling it aborts the call
 // 		recipientID: recipient id to send to
 // 		fileUrl: url of the file
+// 		options: optional settings of the send, see SendOption
 // Output:
 // 		Response from API and an error if exists
-func (bot *Bot) SendFileUrl(recipientID string, fileUrl string) (*http.Response, error) {
-	return bot.SendAttachmentUrl(recipientID, AttachmentTypeFile, fileUrl)
+func (bot *Bot) SendFileUrl(ctx context.Context, recipientID string, fileUrl string, options ...SendOption) (*SendResponse, error) {
+	return bot.SendAttachmentUrl(ctx, recipientID, AttachmentTypeFile, fileUrl, options...)
 }
 
 // Set a get started button for the page, this button will be shown on welcome screen for new users
 // https://developers.facebook.com/docs/messenger-platform/reference/messenger-profile-api/get-started-button
 //
 // Input:
+// 		ctx: context of the request, cancelling it aborts the call
 // 		gsPayload: a Payload object has GetStarted property as described by the API docs
 // Output:
-// 		Response from API and an error if exists
-func (bot *Bot) SetGetStarted(gsPayload Payload) (*http.Response, error) {
-	return bot.sendRaw("/me/messenger_profile", http.MethodPost, gsPayload)
+// 		An error if exists
+func (bot *Bot) SetGetStarted(ctx context.Context, gsPayload Payload) error {
+	return bot.sendRaw(ctx, "/me/messenger_profile", http.MethodPost, gsPayload, nil)
 }
 
 // Remove get started button from the page
 // https://developers.facebook.com/docs/messenger-platform/reference/messenger-profile-api/#delete
 //
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
 // Output:
-// 		Response from API and an error if exists
-func (bot *Bot) RemoveGetStarted() (*http.Response, error) {
+// 		An error if exists
+func (bot *Bot) RemoveGetStarted(ctx context.Context) error {
 	payload := Payload{DeletedFields: []string{"get_started"}}
-	return bot.sendRaw("/me/messenger_profile", http.MethodDelete, payload)
+	return bot.sendRaw(ctx, "/me/messenger_profile", http.MethodDelete, payload, nil)
 }
 
 // Set a persistent menu for the page. You have to set a get started button before use this
 // https://developers.facebook.com/docs/messenger-platform/reference/messenger-profile-api/persistent-menu
 //
 // Input:
+// 		ctx: context of the request, cancelling it aborts the call
 // 		pmPayload: a Payload object which has PersistentMenu property as described by the API docs
 // Output:
-// 		Response from API and an error if exists
-func (bot *Bot) SetPersistentMenu(pmPayload Payload) (*http.Response, error) {
-	return bot.sendRaw("/me/messenger_profile", http.MethodPost, pmPayload)
+// 		An error if exists
+func (bot *Bot) SetPersistentMenu(ctx context.Context, pmPayload Payload) error {
+	return bot.sendRaw(ctx, "/me/messenger_profile", http.MethodPost, pmPayload, nil)
 }
 
 // Remove persistent menu from the page
 // https://developers.facebook.com/docs/messenger-platform/reference/messenger-profile-api/#delete
 //
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
 // Output:
-// 		Response from API and an error if exists
-func (bot *Bot) RemovePersistentMenu() (*http.Response, error) {
+// 		An error if exists
+func (bot *Bot) RemovePersistentMenu(ctx context.Context) error {
 	payload := Payload{DeletedFields: []string{"persistent_menu"}}
-	return bot.sendRaw("/me/messenger_profile", http.MethodDelete, payload)
+	return bot.sendRaw(ctx, "/me/messenger_profile", http.MethodDelete, payload, nil)
 }
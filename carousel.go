@@ -0,0 +1,252 @@
+package messenger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// Maximum number of elements of a generic template
+	genericMaxElements = 10
+
+	carouselPayloadPrefix     = "CAROUSEL|"
+	defaultSeeMoreTitle       = "See more"
+	defaultSeeMoreButtonTitle = "See more"
+)
+
+// ErrUnknownCarousel is returned when a carousel is not registered on the CarouselPager.
+var ErrUnknownCarousel = errors.New("messenger: unknown carousel")
+
+// Carousel is a generic template with any number of elements, sent one page at a time. Every page but
+// the last one ends with a "See more" card whose postback carries the cursor of the next page.
+type Carousel struct {
+	Name               string    // identifies the carousel in the postback payloads, must not contain "|"
+	Elements           []Element // the elements of all pages
+	PageSize           int       // cards per page, "See more" card included, between 2 and 10, 10 when 0
+	SeeMoreTitle       string    // title of the "See more" card, "See more" when empty
+	SeeMoreSubtitle    string    // optional subtitle of the "See more" card
+	SeeMoreButtonTitle string    // title of the button of the "See more" card, "See more" when empty
+}
+
+// CarouselPager sends registered carousels and serves their next pages when the "See more" postbacks
+// come back through the webhook:
+//
+// 		pager := messenger.NewCarouselPager(bot)
+// 		pager.Register(messenger.Carousel{Name: "catalog", Elements: products})
+// 		dispatcher.OnPostback(pager.PostbackHandler(handlePostback))
+// 		pager.Send(ctx, recipientID, "catalog")
+type CarouselPager struct {
+	bot *Bot
+
+	mu        sync.RWMutex
+	carousels map[string]Carousel
+}
+
+// Return the postback payload of the page of a carousel starting at offset.
+//
+// Input:
+// 		name: name of the carousel
+// 		offset: index of the first element of the page
+// Output:
+// 		The payload, "CAROUSEL|name|offset"
+func CarouselPayload(name string, offset int) string {
+	return carouselPayloadPrefix + name + "|" + strconv.Itoa(offset)
+}
+
+// Parse the postback payload of a carousel page.
+//
+// Input:
+// 		payload: payload of a postback
+// Output:
+// 		The name of the carousel, the offset of the page and whether payload is a carousel payload
+func ParseCarouselPayload(payload string) (name string, offset int, ok bool) {
+	if !strings.HasPrefix(payload, carouselPayloadPrefix) {
+		return "", 0, false
+	}
+	rest := payload[len(carouselPayloadPrefix):]
+	i := strings.LastIndex(rest, "|")
+	if i < 0 {
+		return "", 0, false
+	}
+	offset, err := strconv.Atoi(rest[i+1:])
+	if err != nil || offset < 0 {
+		return "", 0, false
+	}
+	return rest[:i], offset, true
+}
+
+// Return the cards of the page starting at offset, with a "See more" card at the end when more
+// elements follow.
+//
+// Input:
+// 		offset: index of the first element of the page, 0 for the first page
+// Output:
+// 		The elements of the page, empty when offset is past the last element
+func (c Carousel) Page(offset int) []Element {
+	if offset < 0 || offset >= len(c.Elements) {
+		return nil
+	}
+	pageSize := c.pageSize()
+	remaining := c.Elements[offset:]
+	if len(remaining) <= pageSize {
+		return append([]Element(nil), remaining...)
+	}
+
+	page := append([]Element(nil), remaining[:pageSize-1]...)
+	return append(page, c.seeMoreElement(offset+pageSize-1))
+}
+
+// Check the carousel can be paginated.
+//
+// Output:
+// 		ValidationErrors if the carousel is invalid, nil otherwise
+func (c Carousel) Validate() error {
+	var errs ValidationErrors
+	errs.required("name", c.Name)
+	if strings.Contains(c.Name, "|") {
+		errs.add("name", "must not contain |")
+	}
+	if len(c.Elements) == 0 {
+		errs.add("elements", "is required")
+	}
+	if c.PageSize != 0 && (c.PageSize < 2 || c.PageSize > genericMaxElements) {
+		errs.add("page_size", "must be between 2 and %d", genericMaxElements)
+	}
+	return errs.err()
+}
+
+func (c Carousel) pageSize() int {
+	if c.PageSize < 2 || c.PageSize > genericMaxElements {
+		return genericMaxElements
+	}
+	return c.PageSize
+}
+
+func (c Carousel) seeMoreElement(next int) Element {
+	title, buttonTitle := c.SeeMoreTitle, c.SeeMoreButtonTitle
+	if title == "" {
+		title = defaultSeeMoreTitle
+	}
+	if buttonTitle == "" {
+		buttonTitle = defaultSeeMoreButtonTitle
+	}
+	return Element{
+		Title:    title,
+		Subtitle: c.SeeMoreSubtitle,
+		Buttons: []Button{{
+			Type:    "postback",
+			Title:   buttonTitle,
+			Payload: CarouselPayload(c.Name, next),
+		}},
+	}
+}
+
+// Create a new CarouselPager instance.
+//
+// Input:
+// 		bot: the Bot which sends the pages
+// Output:
+// 		A CarouselPager instance
+func NewCarouselPager(bot *Bot) *CarouselPager {
+	return &CarouselPager{bot: bot, carousels: make(map[string]Carousel)}
+}
+
+// Register a carousel, replacing the carousel with the same name.
+//
+// Input:
+// 		carousel: a Carousel object
+// Output:
+// 		ValidationErrors if the carousel is invalid
+func (p *CarouselPager) Register(carousel Carousel) error {
+	if err := carousel.Validate(); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.carousels[carousel.Name] = carousel
+	return nil
+}
+
+// Send the first page of a registered carousel to the specified recipient.
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		recipientID: recipient id to send to
+// 		name: name of the carousel
+// 		options: optional settings of the send, see SendOption
+// Output:
+// 		Response from API and an error if exists, ErrUnknownCarousel if the carousel is not registered
+func (p *CarouselPager) Send(ctx context.Context, recipientID string, name string, options ...SendOption) (*SendResponse, error) {
+	return p.SendPage(ctx, recipientID, name, 0, options...)
+}
+
+// Send the page of a registered carousel starting at offset to the specified recipient.
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		recipientID: recipient id to send to
+// 		name: name of the carousel
+// 		offset: index of the first element of the page
+// 		options: optional settings of the send, see SendOption
+// Output:
+// 		Response from API and an error if exists, ErrUnknownCarousel if the carousel is not registered
+func (p *CarouselPager) SendPage(ctx context.Context, recipientID string, name string, offset int, options ...SendOption) (*SendResponse, error) {
+	p.mu.RLock()
+	carousel, ok := p.carousels[name]
+	p.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCarousel, name)
+	}
+
+	elements := carousel.Page(offset)
+	if len(elements) == 0 {
+		return nil, fmt.Errorf("messenger: carousel %s has no page at offset %d", name, offset)
+	}
+	return p.bot.SendGenericMessage(ctx, recipientID, elements, options...)
+}
+
+// Send the next page of a carousel if the postback comes from a "See more" card.
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		recipientID: psid of the user who sent the postback
+// 		postback: the Postback received from Facebook
+// Output:
+// 		Whether the postback is a carousel postback and an error if exists
+func (p *CarouselPager) HandlePostback(ctx context.Context, recipientID string, postback Postback) (bool, error) {
+	name, offset, ok := ParseCarouselPayload(postback.Payload)
+	if !ok {
+		return false, nil
+	}
+	_, err := p.SendPage(ctx, recipientID, name, offset)
+	return true, err
+}
+
+// Wrap a PostbackHandler so that the "See more" postbacks of the carousels are served by the pager,
+// other postbacks and standby events are passed to next. Errors of the sends are logged.
+//
+// Input:
+// 		next: the handler of the other postbacks, can be nil
+// Output:
+// 		A PostbackHandler to register with Dispatcher.OnPostback
+func (p *CarouselPager) PostbackHandler(next PostbackHandler) PostbackHandler {
+	return func(event Event, postback Postback) {
+		if !event.Standby {
+			handled, err := p.HandlePostback(context.Background(), event.Sender.ID, postback)
+			if err != nil {
+				log.Println("messenger: can not send carousel page:", err.Error())
+			}
+			if handled {
+				return
+			}
+		}
+		if next != nil {
+			next(event, postback)
+		}
+	}
+}
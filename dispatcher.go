@@ -0,0 +1,231 @@
+package messenger
+
+import "sync"
+
+type HandoverAction string
+
+const (
+	HandoverActionPass    = HandoverAction("pass_thread_control")
+	HandoverActionTake    = HandoverAction("take_thread_control")
+	HandoverActionRequest = HandoverAction("request_thread_control")
+)
+
+// Event is an EntryMessage together with the entry it was delivered in.
+// Standby is true when the message came from the standby channel, which means
+// the app does not own the thread at the moment.
+type Event struct {
+	EntryMessage
+	PageID  string
+	Standby bool
+}
+
+type (
+	MessageHandler           func(event Event, message WebhookMessage)
+	QuickReplyHandler        func(event Event, message WebhookMessage, quickReply QuickReply)
+	PostbackHandler          func(event Event, postback Postback)
+	ReactionHandler          func(event Event, reaction Reaction)
+	ReadHandler              func(event Event, read MessageRead)
+	DeliveryHandler          func(event Event, delivery MessageDelivery)
+	OptinHandler             func(event Event, optin Optin)
+	ReferralHandler          func(event Event, referral Referral)
+	HandoverHandler          func(event Event, action HandoverAction, handover Handover)
+	PolicyEnforcementHandler func(event Event, policyEnforcement PolicyEnforcement)
+)
+
+// Dispatcher routes every EntryMessage of a WebhookEvent to the registered typed handler.
+// Events without a registered handler are dropped silently.
+//
+// A Dispatcher can be plugged directly into a WebhookHandler:
+//
+// 		dispatcher := messenger.NewDispatcher()
+// 		dispatcher.OnMessage(func(event messenger.Event, message messenger.WebhookMessage) { ... })
+// 		http.Handle("/webhook", messenger.NewWebhookHandler(verifyToken, appSecret, dispatcher.Dispatch))
+type Dispatcher struct {
+	mu       sync.RWMutex
+	handlers dispatcherHandlers
+}
+
+type dispatcherHandlers struct {
+	onMessage           MessageHandler
+	onEcho              MessageHandler
+	onQuickReply        QuickReplyHandler
+	onPostback          PostbackHandler
+	onReaction          ReactionHandler
+	onRead              ReadHandler
+	onDelivery          DeliveryHandler
+	onOptin             OptinHandler
+	onReferral          ReferralHandler
+	onHandover          HandoverHandler
+	onPolicyEnforcement PolicyEnforcementHandler
+}
+
+// Create a new Dispatcher instance without any handler.
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{}
+}
+
+// Register handler for messages sent by users.
+// Quick reply taps also go here when no OnQuickReply handler is registered.
+func (d *Dispatcher) OnMessage(handler MessageHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers.onMessage = handler
+}
+
+// Register handler for message echoes, the messages sent by the page itself.
+func (d *Dispatcher) OnEcho(handler MessageHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers.onEcho = handler
+}
+
+// Register handler for quick reply taps.
+func (d *Dispatcher) OnQuickReply(handler QuickReplyHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers.onQuickReply = handler
+}
+
+// Register handler for postback buttons, get started button and persistent menu items.
+func (d *Dispatcher) OnPostback(handler PostbackHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers.onPostback = handler
+}
+
+// Register handler for message reactions.
+func (d *Dispatcher) OnReaction(handler ReactionHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers.onReaction = handler
+}
+
+// Register handler for message reads.
+func (d *Dispatcher) OnRead(handler ReadHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers.onRead = handler
+}
+
+// Register handler for message deliveries.
+func (d *Dispatcher) OnDelivery(handler DeliveryHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers.onDelivery = handler
+}
+
+// Register handler for plugin opt-ins.
+func (d *Dispatcher) OnOptin(handler OptinHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers.onOptin = handler
+}
+
+// Register handler for referrals from m.me links, ads and plugins.
+func (d *Dispatcher) OnReferral(handler ReferralHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers.onReferral = handler
+}
+
+// Register handler for pass, take and request thread control events of the handover protocol.
+func (d *Dispatcher) OnHandover(handler HandoverHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers.onHandover = handler
+}
+
+// Register handler for policy enforcement notifications.
+func (d *Dispatcher) OnPolicyEnforcement(handler PolicyEnforcementHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers.onPolicyEnforcement = handler
+}
+
+// Dispatch every messaging and standby entry message of a webhook event.
+//
+// Input:
+// 		event: a WebhookEvent received from Facebook
+func (d *Dispatcher) Dispatch(event WebhookEvent) {
+	for _, entry := range event.Entry {
+		if entry.Messaging != nil {
+			for _, message := range *entry.Messaging {
+				d.DispatchEvent(Event{EntryMessage: message, PageID: entry.ID})
+			}
+		}
+		if entry.Standby != nil {
+			for _, message := range *entry.Standby {
+				d.DispatchEvent(Event{EntryMessage: message, PageID: entry.ID, Standby: true})
+			}
+		}
+	}
+}
+
+// Dispatch a single event to its typed handler.
+//
+// Input:
+// 		event: an Event to route
+func (d *Dispatcher) DispatchEvent(event Event) {
+	// Take a snapshot so handlers may register other handlers without deadlocking
+	d.mu.RLock()
+	h := d.handlers
+	d.mu.RUnlock()
+
+	switch {
+	case event.Message != nil:
+		h.dispatchMessage(event, *event.Message)
+	case event.Postback != nil:
+		if h.onPostback != nil {
+			h.onPostback(event, *event.Postback)
+		}
+	case event.Reaction != nil:
+		if h.onReaction != nil {
+			h.onReaction(event, *event.Reaction)
+		}
+	case event.MessageRead != nil:
+		if h.onRead != nil {
+			h.onRead(event, *event.MessageRead)
+		}
+	case event.MessageDelivery != nil:
+		if h.onDelivery != nil {
+			h.onDelivery(event, *event.MessageDelivery)
+		}
+	case event.Optin != nil:
+		if h.onOptin != nil {
+			h.onOptin(event, *event.Optin)
+		}
+	case event.Referral != nil:
+		if h.onReferral != nil {
+			h.onReferral(event, *event.Referral)
+		}
+	case event.PassThreadControl != nil:
+		h.dispatchHandover(event, HandoverActionPass, *event.PassThreadControl)
+	case event.TakeThreadControl != nil:
+		h.dispatchHandover(event, HandoverActionTake, *event.TakeThreadControl)
+	case event.RequestThreadControl != nil:
+		h.dispatchHandover(event, HandoverActionRequest, *event.RequestThreadControl)
+	case event.PolicyEnforcement != nil:
+		if h.onPolicyEnforcement != nil {
+			h.onPolicyEnforcement(event, *event.PolicyEnforcement)
+		}
+	}
+}
+
+func (h dispatcherHandlers) dispatchMessage(event Event, message WebhookMessage) {
+	switch {
+	case message.IsEcho:
+		if h.onEcho != nil {
+			h.onEcho(event, message)
+		}
+	case message.QuickReply != nil && h.onQuickReply != nil:
+		h.onQuickReply(event, message, *message.QuickReply)
+	case h.onMessage != nil:
+		h.onMessage(event, message)
+	}
+}
+
+func (h dispatcherHandlers) dispatchHandover(event Event, action HandoverAction, handover Handover) {
+	if h.onHandover != nil {
+		h.onHandover(event, action, handover)
+	}
+}
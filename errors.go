@@ -0,0 +1,144 @@
+package messenger
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Error codes and subcodes returned by Graph API
+// https://developers.facebook.com/docs/messenger-platform/reference/send-api/error-codes
+const (
+	ErrorCodeUnknown            = 1
+	ErrorCodeServiceUnavailable = 2
+	ErrorCodeAppRateLimit       = 4
+	ErrorCodePermissionDenied   = 10
+	ErrorCodeUserRateLimit      = 17
+	ErrorCodePageRateLimit      = 32
+	ErrorCodeInvalidParameter   = 100
+	ErrorCodeSessionKeyInvalid  = 102
+	ErrorCodeAccessTokenInvalid = 190
+	ErrorCodePermissionMin      = 200
+	ErrorCodePermissionMax      = 299
+	ErrorCodeUserUnavailable    = 551
+	ErrorCodeSendRateLimit      = 613
+
+	ErrorSubcodeNoMatchingUser       = 2018001
+	ErrorSubcodeOutsideAllowedWindow = 2018278
+	ErrorSubcodeUserCannotReceive    = 2018108
+	ErrorSubcodePersonUnavailable    = 1545041
+)
+
+// GraphError is the error envelope returned by Graph API for an unsuccessful request.
+// https://developers.facebook.com/docs/graph-api/using-graph-api/error-handling
+type GraphError struct {
+	StatusCode   int           `json:"-"`
+	RetryAfter   time.Duration `json:"-"` // delay asked by the Retry-After header, zero if absent
+	Message      string        `json:"message"`
+	Type         string        `json:"type"`
+	Code         int           `json:"code"`
+	ErrorSubcode int           `json:"error_subcode,omitempty"`
+	FbtraceID    string        `json:"fbtrace_id,omitempty"`
+	IsTransient  bool          `json:"is_transient,omitempty"`
+	UserTitle    string        `json:"error_user_title,omitempty"`
+	UserMessage  string        `json:"error_user_msg,omitempty"`
+}
+
+type graphErrorEnvelope struct {
+	Error *GraphError `json:"error"`
+}
+
+// Decode the error envelope from a response body. If the body is not a Graph error,
+// the returned GraphError only carries the http status and the raw body as message.
+func newGraphError(statusCode int, body []byte) *GraphError {
+	var envelope graphErrorEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Error == nil {
+		message := string(body)
+		if message == "" {
+			message = http.StatusText(statusCode)
+		}
+		return &GraphError{StatusCode: statusCode, Message: message}
+	}
+	envelope.Error.StatusCode = statusCode
+	return envelope.Error
+}
+
+func (e *GraphError) Error() string {
+	s := "messenger: graph error"
+	if e.Code != 0 {
+		s += " (#" + strconv.Itoa(e.Code)
+		if e.ErrorSubcode != 0 {
+			s += "/" + strconv.Itoa(e.ErrorSubcode)
+		}
+		s += ")"
+	} else if e.StatusCode != 0 {
+		s += " (http " + strconv.Itoa(e.StatusCode) + ")"
+	}
+	if e.Type != "" {
+		s += " " + e.Type
+	}
+	s += ": " + e.Message
+	if e.FbtraceID != "" {
+		s += " [fbtrace_id " + e.FbtraceID + "]"
+	}
+	return s
+}
+
+// Report whether the request was throttled by the app, user, page or send rate limit.
+func (e *GraphError) IsRateLimited() bool {
+	switch e.Code {
+	case ErrorCodeAppRateLimit, ErrorCodeUserRateLimit, ErrorCodePageRateLimit, ErrorCodeSendRateLimit:
+		return true
+	}
+	return false
+}
+
+// Report whether the recipient can not be messaged, e.g. the user blocked the page or does not exist.
+func (e *GraphError) IsUserUnavailable() bool {
+	if e.Code == ErrorCodeUserUnavailable {
+		return true
+	}
+	switch e.ErrorSubcode {
+	case ErrorSubcodeNoMatchingUser, ErrorSubcodeUserCannotReceive, ErrorSubcodePersonUnavailable:
+		return true
+	}
+	return false
+}
+
+// Report whether the app or page lacks a permission needed for the request,
+// including sends outside of the allowed messaging window.
+func (e *GraphError) IsPermissionError() bool {
+	return e.Code == ErrorCodePermissionDenied ||
+		(e.Code >= ErrorCodePermissionMin && e.Code <= ErrorCodePermissionMax)
+}
+
+// Report whether the access token is expired or otherwise invalid.
+func (e *GraphError) IsTokenExpired() bool {
+	return e.Code == ErrorCodeAccessTokenInvalid || e.Code == ErrorCodeSessionKeyInvalid
+}
+
+// Report whether err is a GraphError caused by rate limiting.
+func IsRateLimited(err error) bool {
+	var graphErr *GraphError
+	return errors.As(err, &graphErr) && graphErr.IsRateLimited()
+}
+
+// Report whether err is a GraphError caused by an unavailable recipient.
+func IsUserUnavailable(err error) bool {
+	var graphErr *GraphError
+	return errors.As(err, &graphErr) && graphErr.IsUserUnavailable()
+}
+
+// Report whether err is a GraphError caused by a missing permission.
+func IsPermissionError(err error) bool {
+	var graphErr *GraphError
+	return errors.As(err, &graphErr) && graphErr.IsPermissionError()
+}
+
+// Report whether err is a GraphError caused by an expired or invalid access token.
+func IsTokenExpired(err error) bool {
+	var graphErr *GraphError
+	return errors.As(err, &graphErr) && graphErr.IsTokenExpired()
+}
@@ -0,0 +1,171 @@
+package messenger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// PageInboxAppID is the app id of the Page Inbox, pass the thread control to it
+// to let human agents answer in the inbox of the page.
+const PageInboxAppID = "263902037430900"
+
+var errHandoverFailed = errors.New("messenger: handover request was not successful")
+
+type (
+	// ThreadOwner is the app which currently controls a conversation.
+	ThreadOwner struct {
+		AppID string `json:"app_id"`
+	}
+
+	// SecondaryReceiver is an app allowed to receive standby events and to take or request thread control.
+	SecondaryReceiver struct {
+		ID   string `json:"id"`
+		Name string `json:"name,omitempty"`
+	}
+
+	// handoverRequest is the body of the handover protocol endpoints
+	handoverRequest struct {
+		Recipient   *Recipient  `json:"recipient"`
+		TargetAppID json.Number `json:"target_app_id,omitempty"`
+		Metadata    string      `json:"metadata,omitempty"`
+	}
+
+	handoverResponse struct {
+		Success bool `json:"success"`
+	}
+)
+
+// Pass the thread control of a conversation to another app.
+// https://developers.facebook.com/docs/messenger-platform/reference/handover-protocol/pass-thread-control
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		recipientID: psid of the user in the conversation
+// 		targetAppID: app id of the new thread owner, e.g. PageInboxAppID
+// 		metadata: optional string passed to the new thread owner
+// Output:
+// 		An error if exists, the request is not sent when targetAppID is not numeric
+func (bot *Bot) PassThreadControl(ctx context.Context, recipientID string, targetAppID string, metadata string) error {
+	if _, err := strconv.ParseUint(targetAppID, 10, 64); err != nil {
+		return fmt.Errorf("messenger: target app id must be numeric, got %q", targetAppID)
+	}
+	request := handoverRequest{
+		Recipient:   &Recipient{ID: recipientID},
+		TargetAppID: json.Number(targetAppID),
+		Metadata:    metadata,
+	}
+	return bot.sendHandover(ctx, "/me/pass_thread_control", request)
+}
+
+// Take the thread control of a conversation from the current owner, only available to the primary receiver.
+// https://developers.facebook.com/docs/messenger-platform/reference/handover-protocol/take-thread-control
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		recipientID: psid of the user in the conversation
+// 		metadata: optional string passed to the previous thread owner
+// Output:
+// 		An error if exists
+func (bot *Bot) TakeThreadControl(ctx context.Context, recipientID string, metadata string) error {
+	request := handoverRequest{
+		Recipient: &Recipient{ID: recipientID},
+		Metadata:  metadata,
+	}
+	return bot.sendHandover(ctx, "/me/take_thread_control", request)
+}
+
+// Ask the primary receiver for the thread control of a conversation, only available to secondary receivers.
+// https://developers.facebook.com/docs/messenger-platform/reference/handover-protocol/request-thread-control
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		recipientID: psid of the user in the conversation
+// 		metadata: optional string passed to the primary receiver
+// Output:
+// 		An error if exists
+func (bot *Bot) RequestThreadControl(ctx context.Context, recipientID string, metadata string) error {
+	request := handoverRequest{
+		Recipient: &Recipient{ID: recipientID},
+		Metadata:  metadata,
+	}
+	return bot.sendHandover(ctx, "/me/request_thread_control", request)
+}
+
+// Release the thread control of a conversation back to the primary receiver.
+// https://developers.facebook.com/docs/messenger-platform/reference/handover-protocol/release-thread-control
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		recipientID: psid of the user in the conversation
+// 		metadata: optional string passed to the primary receiver
+// Output:
+// 		An error if exists
+func (bot *Bot) ReleaseThreadControl(ctx context.Context, recipientID string, metadata string) error {
+	request := handoverRequest{
+		Recipient: &Recipient{ID: recipientID},
+		Metadata:  metadata,
+	}
+	return bot.sendHandover(ctx, "/me/release_thread_control", request)
+}
+
+// Get the app which currently controls a conversation.
+// https://developers.facebook.com/docs/messenger-platform/reference/handover-protocol/thread-owner-api
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		recipientID: psid of the user in the conversation
+// Output:
+// 		The ThreadOwner and an error if exists
+func (bot *Bot) GetThreadOwner(ctx context.Context, recipientID string) (*ThreadOwner, error) {
+	query := url.Values{}
+	query.Set("recipient", recipientID)
+
+	var resp struct {
+		Data []struct {
+			ThreadOwner ThreadOwner `json:"thread_owner"`
+		} `json:"data"`
+	}
+	if err := bot.get(ctx, "/me/thread_owner", query, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Data) == 0 {
+		return nil, errors.New("messenger: thread owner not found")
+	}
+	return &resp.Data[0].ThreadOwner, nil
+}
+
+// Get the apps of the page which are secondary receivers, only available to the primary receiver.
+// https://developers.facebook.com/docs/messenger-platform/reference/handover-protocol/secondary-receivers-api
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// Output:
+// 		The secondary receivers and an error if exists
+func (bot *Bot) GetSecondaryReceivers(ctx context.Context) ([]SecondaryReceiver, error) {
+	query := url.Values{}
+	query.Set("fields", "id,name")
+
+	var resp struct {
+		Data []SecondaryReceiver `json:"data"`
+	}
+	if err := bot.get(ctx, "/me/secondary_receivers", query, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func (bot *Bot) sendHandover(ctx context.Context, requestSubPath string, request handoverRequest) error {
+	var resp handoverResponse
+	if err := bot.sendRaw(ctx, requestSubPath, http.MethodPost, request, &resp); err != nil {
+		return err
+	}
+	if !resp.Success {
+		return errHandoverFailed
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package messenger
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPassThreadControl(t *testing.T) {
+	var body map[string]interface{}
+	server, attempts := newGraphServer(t, func(w http.ResponseWriter, r *http.Request, n int) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("can not decode body: %v", err)
+		}
+		w.Write([]byte(`{"success":true}`))
+	})
+	bot := NewBot("token", "", WithGraphUrl(server.URL))
+
+	if err := bot.PassThreadControl(context.Background(), "1", PageInboxAppID, "agent"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]interface{}{
+		"recipient":     map[string]interface{}{"id": "1"},
+		"target_app_id": 263902037430900.0,
+		"metadata":      "agent",
+	}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("body = %v, want %v", body, want)
+	}
+
+	for _, appID := range []string{"", "inbox", "12a", "-1"} {
+		if err := bot.PassThreadControl(context.Background(), "1", appID, ""); err == nil {
+			t.Errorf("app id %q: want an error", appID)
+		}
+	}
+	if n := atomic.LoadInt32(attempts); n != 1 {
+		t.Errorf("requests = %d, want 1, invalid app ids must not be sent", n)
+	}
+}
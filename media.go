@@ -0,0 +1,95 @@
+package messenger
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// Maximum number of buttons of a media element
+const mediaMaxButtons = 1
+
+// MediaElement is the image or video of a media template, given by exactly one of an uploaded
+// attachment id or the url of the media on Facebook, with up to one button.
+// https://developers.facebook.com/docs/messenger-platform/send-messages/template/media
+type MediaElement struct {
+	MediaType    AttachmentType // AttachmentTypeImage or AttachmentTypeVideo
+	AttachmentID string         // id returned by UploadAttachment
+	URL          string         // url of a photo or video posted on Facebook, e.g. https://www.facebook.com/{page}/videos/{id}/
+	Buttons      []Button       // up to 1 button
+}
+
+// Convert the media element to an Element of the media template.
+func (m MediaElement) Element() Element {
+	return Element{
+		MediaType:    m.MediaType,
+		AttachmentID: m.AttachmentID,
+		URL:          m.URL,
+		Buttons:      m.Buttons,
+	}
+}
+
+// Convert the media element to an Attachment to send in a message.
+func (m MediaElement) Attachment() Attachment {
+	return Attachment{
+		Type: AttachmentTypeTemplate,
+		Payload: AttachmentPayload{
+			TemplateType: TemplateTypeMedia,
+			Elements:     []Element{m.Element()},
+		},
+	}
+}
+
+// Check the media element against the rules of the media template.
+//
+// Output:
+// 		ValidationErrors if the media element is invalid, nil otherwise
+func (m MediaElement) Validate() error {
+	var errs ValidationErrors
+	switch m.MediaType {
+	case AttachmentTypeImage, AttachmentTypeVideo:
+	case "":
+		errs.add("media_type", "is required")
+	default:
+		errs.add("media_type", "must be image or video")
+	}
+
+	switch {
+	case m.AttachmentID == "" && m.URL == "":
+		errs.add("", "one of attachment_id and url must be set")
+	case m.AttachmentID != "" && m.URL != "":
+		errs.add("", "only one of attachment_id and url can be set")
+	case m.URL != "" && !isFacebookURL(m.URL):
+		errs.add("url", "must be the url of a media posted on facebook.com")
+	}
+
+	errs.maxItems("buttons", len(m.Buttons), mediaMaxButtons)
+	return errs.err()
+}
+
+// Send a media template to the specified recipient, the media element is validated before it is sent.
+// https://developers.facebook.com/docs/messenger-platform/send-messages/template/media
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		recipientID: recipient id to send to
+// 		element: a MediaElement object
+// 		options: optional settings of the send, see SendOption
+// Output:
+// 		Response from API and an error if exists, ValidationErrors if the media element is invalid
+func (bot *Bot) SendMediaTemplate(ctx context.Context, recipientID string, element MediaElement, options ...SendOption) (*SendResponse, error) {
+	if err := element.Validate(); err != nil {
+		return nil, err
+	}
+	return bot.SendAttachmentMessage(ctx, recipientID, element.Attachment(), options...)
+}
+
+// Report whether rawURL is a https url of facebook.com or one of its subdomains
+func isFacebookURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	return host == "facebook.com" || strings.HasSuffix(host, ".facebook.com")
+}
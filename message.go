@@ -1,9 +1,11 @@
 package messenger
 
 type (
-	TemplateType   string
-	AttachmentType string
-	QuickReplyType string
+	TemplateType                  string
+	AttachmentType                string
+	QuickReplyType                string
+	NotificationMessagesFrequency string
+	NotificationMessagesReoptin   string
 )
 
 const (
@@ -19,6 +21,21 @@ const (
 	TemplateTypeAirline = TemplateType("airline_boardingpass")
 	TemplateTypeMedia   = TemplateType("media")
 
+	TemplateTypeAirlineBoardingPass = TemplateType("airline_boardingpass")
+	TemplateTypeAirlineItinerary    = TemplateType("airline_itinerary")
+	TemplateTypeAirlineCheckin      = TemplateType("airline_checkin")
+	TemplateTypeAirlineUpdate       = TemplateType("airline_update")
+
+	TemplateTypeOneTimeNotifReq      = TemplateType("one_time_notif_req")
+	TemplateTypeNotificationMessages = TemplateType("notification_messages")
+
+	NotificationMessagesFrequencyDaily   = NotificationMessagesFrequency("DAILY")
+	NotificationMessagesFrequencyWeekly  = NotificationMessagesFrequency("WEEKLY")
+	NotificationMessagesFrequencyMonthly = NotificationMessagesFrequency("MONTHLY")
+
+	NotificationMessagesReoptinEnabled  = NotificationMessagesReoptin("ENABLED")
+	NotificationMessagesReoptinDisabled = NotificationMessagesReoptin("DISABLED")
+
 	QuickReplyTypeText            = QuickReplyType("text")
 	QuickReplyTypeUserPhoneNumber = QuickReplyType("user_phone_number")
 	QuickReplyTypeUserEmail       = QuickReplyType("user_email")
@@ -40,10 +57,52 @@ type (
 	AttachmentPayload struct {
 		TemplateType TemplateType `json:"template_type,omitempty"`
 		Text         string       `json:"text,omitempty"`
+		Title        string       `json:"title,omitempty"`
+		Payload      string       `json:"payload,omitempty"`
 		Elements     []Element    `json:"elements,omitempty"`
 		Buttons      []Button     `json:"buttons,omitempty"`
 		URL          string       `json:"url,omitempty"`
+		AttachmentID string       `json:"attachment_id,omitempty"`
+		IsReusable   bool         `json:"is_reusable,omitempty"`
 		StickerID    *int         `json:"sticker_id,omitempty"`
+
+		// Fields of the notification_messages template
+		ImageURL                      string                        `json:"image_url,omitempty"`
+		NotificationMessagesFrequency NotificationMessagesFrequency `json:"notification_messages_frequency,omitempty"`
+		NotificationMessagesReoptin   NotificationMessagesReoptin   `json:"notification_messages_reoptin,omitempty"`
+		NotificationMessagesTimezone  string                        `json:"notification_messages_timezone,omitempty"`
+		NotificationMessagesCTAText   string                        `json:"notification_messages_cta_text,omitempty"`
+
+		// Fields of the receipt template, see ReceiptTemplate
+		RecipientName string              `json:"recipient_name,omitempty"`
+		MerchantName  string              `json:"merchant_name,omitempty"`
+		OrderNumber   string              `json:"order_number,omitempty"`
+		Currency      string              `json:"currency,omitempty"`
+		PaymentMethod string              `json:"payment_method,omitempty"`
+		OrderURL      string              `json:"order_url,omitempty"`
+		Timestamp     string              `json:"timestamp,omitempty"`
+		Sharable      bool                `json:"sharable,omitempty"`
+		Address       *ReceiptAddress     `json:"address,omitempty"`
+		Summary       *ReceiptSummary     `json:"summary,omitempty"`
+		Adjustments   []ReceiptAdjustment `json:"adjustments,omitempty"`
+
+		// Fields of the airline templates, see AirlineBoardingPassTemplate, AirlineItineraryTemplate,
+		// AirlineCheckinTemplate and AirlineUpdateTemplate
+		IntroMessage         string                        `json:"intro_message,omitempty"`
+		Locale               string                        `json:"locale,omitempty"`
+		ThemeColor           string                        `json:"theme_color,omitempty"`
+		PNRNumber            string                        `json:"pnr_number,omitempty"`
+		CheckinURL           string                        `json:"checkin_url,omitempty"`
+		UpdateType           AirlineUpdateType             `json:"update_type,omitempty"`
+		BoardingPass         []AirlineBoardingPass         `json:"boarding_pass,omitempty"`
+		PassengerInfo        []AirlinePassengerInfo        `json:"passenger_info,omitempty"`
+		FlightInfo           []AirlineFlightInfo           `json:"flight_info,omitempty"`
+		UpdateFlightInfo     *AirlineFlightInfo            `json:"update_flight_info,omitempty"`
+		PassengerSegmentInfo []AirlinePassengerSegmentInfo `json:"passenger_segment_info,omitempty"`
+		PriceInfo            []AirlinePriceInfo            `json:"price_info,omitempty"`
+		BasePrice            string                        `json:"base_price,omitempty"`
+		Tax                  string                        `json:"tax,omitempty"`
+		TotalPrice           string                        `json:"total_price,omitempty"`
 	}
 
 	Button struct {
@@ -54,11 +113,21 @@ type (
 	}
 
 	Element struct {
-		Title         string         `json:"title"`
+		Title         string         `json:"title,omitempty"`
 		Subtitle      string         `json:"subtitle,omitempty"`
 		ImageURL      string         `json:"image_url,omitempty"`
 		DefaultAction *DefaultAction `json:"default_action,omitempty"`
 		Buttons       []Button       `json:"buttons,omitempty"`
+
+		// Fields of a receipt element
+		Quantity int      `json:"quantity,omitempty"`
+		Price    *float64 `json:"price,omitempty"`
+		Currency string   `json:"currency,omitempty"`
+
+		// Fields of a media element, see MediaElement
+		MediaType    AttachmentType `json:"media_type,omitempty"`
+		AttachmentID string         `json:"attachment_id,omitempty"`
+		URL          string         `json:"url,omitempty"`
 	}
 
 	DefaultAction struct {
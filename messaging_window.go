@@ -0,0 +1,189 @@
+package messenger
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// StandardMessagingWindow is how long after the last interaction of a user a page may message the user
+// without a message tag.
+// https://developers.facebook.com/docs/messenger-platform/policy/policy-overview#standard_messaging
+const StandardMessagingWindow = 24 * time.Hour
+
+// ErrOutsideMessagingWindow is returned by a send which a WindowTracker refuses because the standard
+// messaging window of the recipient is closed.
+var ErrOutsideMessagingWindow = errors.New("messenger: recipient is outside the standard messaging window")
+
+// InteractionStore persists the time of the last interaction of every user.
+// Implementations must be safe for concurrent use.
+type InteractionStore interface {
+	// LastInteraction returns the time of the last interaction of the user, ok is false when there is none
+	LastInteraction(psid string) (at time.Time, ok bool, err error)
+	// RecordInteraction stores the time of the last interaction of the user
+	RecordInteraction(psid string, at time.Time) error
+}
+
+// WindowTracker records the last interaction of every user from incoming webhook events and tells whether
+// the standard messaging window of a user is open. Installed on a Bot with WithWindowGuard, it checks
+// every RESPONSE and UPDATE message before it is sent, messages with a tag are not checked.
+//
+// Feed it with every webhook event, e.g. in front of a Dispatcher:
+//
+// 		tracker := messenger.NewWindowTracker(messenger.NewMemoryInteractionStore())
+// 		handler := messenger.NewWebhookHandler(verifyToken, appSecret, func(event messenger.WebhookEvent) {
+// 			tracker.ObserveEvent(event)
+// 			dispatcher.Dispatch(event)
+// 		})
+type WindowTracker struct {
+	store  InteractionStore
+	window time.Duration
+}
+
+type windowGuard struct {
+	tracker     *WindowTracker
+	fallbackTag MessageTag
+}
+
+// Create a new WindowTracker instance with the standard messaging window.
+//
+// Input:
+// 		store: where the interactions are kept, e.g. NewMemoryInteractionStore()
+// Output:
+// 		A WindowTracker instance
+func NewWindowTracker(store InteractionStore) *WindowTracker {
+	return &WindowTracker{store: store, window: StandardMessagingWindow}
+}
+
+// Check the messaging window of every message sent by the Bot, see WindowTracker.
+//
+// Input:
+// 		tracker: the WindowTracker to use
+// 		fallbackTag: tag set on messages sent outside of the window, empty refuses them with ErrOutsideMessagingWindow
+func WithWindowGuard(tracker *WindowTracker, fallbackTag MessageTag) BotOption {
+	return func(bot *Bot) {
+		bot.windowGuard = &windowGuard{tracker: tracker, fallbackTag: fallbackTag}
+	}
+}
+
+// Record the interactions of every messaging and standby entry message of a webhook event.
+//
+// Input:
+// 		event: a WebhookEvent received from Facebook
+// Output:
+// 		The first error of the store if exists
+func (t *WindowTracker) ObserveEvent(event WebhookEvent) error {
+	var firstErr error
+	for _, entry := range event.Entry {
+		for _, messages := range []*[]EntryMessage{entry.Messaging, entry.Standby} {
+			if messages == nil {
+				continue
+			}
+			for _, message := range *messages {
+				if err := t.Observe(message); err != nil && firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+	}
+	return firstErr
+}
+
+// Record the interaction of an entry message, if the user started it. Messages, quick replies, postbacks,
+// reactions, opt-ins and referrals open the window, echoes, deliveries and reads do not.
+//
+// Input:
+// 		message: an EntryMessage received from Facebook
+// Output:
+// 		An error of the store if exists
+func (t *WindowTracker) Observe(message EntryMessage) error {
+	if !isUserInteraction(message) || message.Sender.ID == "" {
+		return nil
+	}
+
+	at := time.Now()
+	if message.Timestamp > 0 {
+		at = time.Unix(0, message.Timestamp*int64(time.Millisecond))
+	}
+
+	last, ok, err := t.store.LastInteraction(message.Sender.ID)
+	if err != nil {
+		return err
+	}
+	if ok && !at.After(last) {
+		return nil
+	}
+	return t.store.RecordInteraction(message.Sender.ID, at)
+}
+
+// Report whether the standard messaging window of the user is open at the given time.
+// A user without any recorded interaction is outside of the window.
+//
+// Input:
+// 		psid: page-scoped id of the user
+// 		now: the time to check
+// Output:
+// 		Whether the window is open and an error of the store if exists
+func (t *WindowTracker) InWindow(psid string, now time.Time) (bool, error) {
+	last, ok, err := t.store.LastInteraction(psid)
+	if err != nil || !ok {
+		return false, err
+	}
+	return now.Sub(last) < t.window, nil
+}
+
+// Check a payload before it is sent: a RESPONSE or UPDATE message to a closed window is tagged
+// with the fallback tag, or refused when there is none
+func (g *windowGuard) check(payload *Payload) error {
+	if payload.Message == nil || payload.Recipient == nil || payload.Recipient.ID == "" ||
+		payload.MessagingType == MessagingTypeMessageTag {
+		return nil
+	}
+
+	open, err := g.tracker.InWindow(payload.Recipient.ID, time.Now())
+	if err != nil || open {
+		return err
+	}
+	if g.fallbackTag == "" {
+		return fmt.Errorf("%w: %s", ErrOutsideMessagingWindow, payload.Recipient.ID)
+	}
+	payload.MessagingType = MessagingTypeMessageTag
+	payload.Tag = g.fallbackTag
+	return nil
+}
+
+func isUserInteraction(message EntryMessage) bool {
+	switch {
+	case message.Message != nil:
+		return !message.Message.IsEcho
+	case message.Postback != nil, message.Reaction != nil, message.Optin != nil, message.Referral != nil:
+		return true
+	}
+	return false
+}
+
+// MemoryInteractionStore is an InteractionStore which keeps the interactions in memory.
+type MemoryInteractionStore struct {
+	mu           sync.RWMutex
+	interactions map[string]time.Time
+}
+
+// Create a new, empty MemoryInteractionStore instance.
+func NewMemoryInteractionStore() *MemoryInteractionStore {
+	return &MemoryInteractionStore{interactions: make(map[string]time.Time)}
+}
+
+func (s *MemoryInteractionStore) LastInteraction(psid string) (time.Time, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	at, ok := s.interactions[psid]
+	return at, ok, nil
+}
+
+func (s *MemoryInteractionStore) RecordInteraction(psid string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.interactions[psid] = at
+	return nil
+}
@@ -0,0 +1,260 @@
+package messenger
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type MessengerProfileField string
+
+const (
+	MessengerProfileFieldGetStarted         = MessengerProfileField("get_started")
+	MessengerProfileFieldPersistentMenu     = MessengerProfileField("persistent_menu")
+	MessengerProfileFieldGreeting           = MessengerProfileField("greeting")
+	MessengerProfileFieldIceBreakers        = MessengerProfileField("ice_breakers")
+	MessengerProfileFieldWhitelistedDomains = MessengerProfileField("whitelisted_domains")
+	MessengerProfileFieldAccountLinkingURL  = MessengerProfileField("account_linking_url")
+	MessengerProfileFieldHomeURL            = MessengerProfileField("home_url")
+
+	// Placeholders replaced in a greeting text with the name of the user
+	GreetingUserFirstName = "{{user_first_name}}"
+	GreetingUserLastName  = "{{user_last_name}}"
+	GreetingUserFullName  = "{{user_full_name}}"
+
+	// Locale of the greeting and persistent menu shown when no other locale matches
+	DefaultLocale = "default"
+)
+
+// AllMessengerProfileFields are the fields read by GetMessengerProfile when no field is given.
+var AllMessengerProfileFields = []MessengerProfileField{
+	MessengerProfileFieldGetStarted,
+	MessengerProfileFieldPersistentMenu,
+	MessengerProfileFieldGreeting,
+	MessengerProfileFieldIceBreakers,
+	MessengerProfileFieldWhitelistedDomains,
+	MessengerProfileFieldAccountLinkingURL,
+	MessengerProfileFieldHomeURL,
+}
+
+type (
+	// MessengerProfile holds the Messenger settings of the page.
+	// https://developers.facebook.com/docs/messenger-platform/reference/messenger-profile-api
+	MessengerProfile struct {
+		GetStarted         *GetStarted      `json:"get_started,omitempty"`
+		PersistentMenu     []PersistentMenu `json:"persistent_menu,omitempty"`
+		Greeting           []Greeting       `json:"greeting,omitempty"`
+		IceBreakers        []IceBreaker     `json:"ice_breakers,omitempty"`
+		WhitelistedDomains []string         `json:"whitelisted_domains,omitempty"`
+		AccountLinkingURL  string           `json:"account_linking_url,omitempty"`
+		HomeURL            *HomeURL         `json:"home_url,omitempty"`
+	}
+
+	// Greeting is the text shown on the welcome screen for a locale, it may contain
+	// GreetingUserFirstName, GreetingUserLastName and GreetingUserFullName placeholders.
+	Greeting struct {
+		Locale string `json:"locale"`
+		Text   string `json:"text"`
+	}
+
+	IceBreaker struct {
+		Question string `json:"question"`
+		Payload  string `json:"payload"`
+	}
+
+	HomeURL struct {
+		URL                string `json:"url"`
+		WebviewHeightRatio string `json:"webview_height_ratio"` // only "tall" is supported
+		WebviewShareButton string `json:"webview_share_button,omitempty"`
+		InTest             bool   `json:"in_test"`
+	}
+)
+
+// Get the Messenger settings of the page.
+// https://developers.facebook.com/docs/messenger-platform/reference/messenger-profile-api#get
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		fields: fields to read, AllMessengerProfileFields if none is given
+// Output:
+// 		The MessengerProfile, with only the fields which are set, and an error if exists
+func (bot *Bot) GetMessengerProfile(ctx context.Context, fields ...MessengerProfileField) (*MessengerProfile, error) {
+	if len(fields) == 0 {
+		fields = AllMessengerProfileFields
+	}
+	query := url.Values{}
+	query.Set("fields", strings.Join(messengerProfileFieldNames(fields), ","))
+
+	var resp struct {
+		Data []MessengerProfile `json:"data"`
+	}
+	if err := bot.get(ctx, "/me/messenger_profile", query, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Data) == 0 {
+		return &MessengerProfile{}, nil
+	}
+	return &resp.Data[0], nil
+}
+
+// Set the given Messenger settings of the page, fields which are not set in profile are left unchanged.
+// https://developers.facebook.com/docs/messenger-platform/reference/messenger-profile-api#post
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		profile: the settings to set
+// Output:
+// 		An error if exists
+func (bot *Bot) SetMessengerProfile(ctx context.Context, profile MessengerProfile) error {
+	payload := Payload{
+		GetStarted:         profile.GetStarted,
+		PersistentMenu:     profile.PersistentMenu,
+		Greeting:           profile.Greeting,
+		IceBreakers:        profile.IceBreakers,
+		WhitelistedDomains: profile.WhitelistedDomains,
+		AccountLinkingURL:  profile.AccountLinkingURL,
+		HomeURL:            profile.HomeURL,
+	}
+	return bot.sendRaw(ctx, "/me/messenger_profile", http.MethodPost, payload, nil)
+}
+
+// Remove the given Messenger settings of the page.
+// https://developers.facebook.com/docs/messenger-platform/reference/messenger-profile-api#delete
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		fields: fields to remove
+// Output:
+// 		An error if exists
+func (bot *Bot) RemoveMessengerProfile(ctx context.Context, fields ...MessengerProfileField) error {
+	if len(fields) == 0 {
+		return errors.New("messenger: no messenger profile field to remove")
+	}
+	payload := Payload{DeletedFields: messengerProfileFieldNames(fields)}
+	return bot.sendRaw(ctx, "/me/messenger_profile", http.MethodDelete, payload, nil)
+}
+
+// Set the greeting shown on the welcome screen, one per locale
+// https://developers.facebook.com/docs/messenger-platform/reference/messenger-profile-api/greeting
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		greetings: an array of Greeting objects, one of them must use DefaultLocale
+// Output:
+// 		An error if exists
+func (bot *Bot) SetGreeting(ctx context.Context, greetings []Greeting) error {
+	return bot.SetMessengerProfile(ctx, MessengerProfile{Greeting: greetings})
+}
+
+// Remove greeting from the page
+// https://developers.facebook.com/docs/messenger-platform/reference/messenger-profile-api/#delete
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// Output:
+// 		An error if exists
+func (bot *Bot) RemoveGreeting(ctx context.Context) error {
+	return bot.RemoveMessengerProfile(ctx, MessengerProfileFieldGreeting)
+}
+
+// Set the ice breakers, frequently asked questions shown to users starting a conversation
+// https://developers.facebook.com/docs/messenger-platform/reference/messenger-profile-api/ice-breakers
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		iceBreakers: an array of IceBreaker objects, up to 4 elements
+// Output:
+// 		An error if exists
+func (bot *Bot) SetIceBreakers(ctx context.Context, iceBreakers []IceBreaker) error {
+	return bot.SetMessengerProfile(ctx, MessengerProfile{IceBreakers: iceBreakers})
+}
+
+// Remove ice breakers from the page
+// https://developers.facebook.com/docs/messenger-platform/reference/messenger-profile-api/#delete
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// Output:
+// 		An error if exists
+func (bot *Bot) RemoveIceBreakers(ctx context.Context) error {
+	return bot.RemoveMessengerProfile(ctx, MessengerProfileFieldIceBreakers)
+}
+
+// Set the domains allowed in webviews and the Messenger Extensions SDK
+// https://developers.facebook.com/docs/messenger-platform/reference/messenger-profile-api/domain-whitelisting
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		domains: an array of https urls, up to 50 elements
+// Output:
+// 		An error if exists
+func (bot *Bot) SetWhitelistedDomains(ctx context.Context, domains []string) error {
+	return bot.SetMessengerProfile(ctx, MessengerProfile{WhitelistedDomains: domains})
+}
+
+// Remove whitelisted domains from the page
+// https://developers.facebook.com/docs/messenger-platform/reference/messenger-profile-api/#delete
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// Output:
+// 		An error if exists
+func (bot *Bot) RemoveWhitelistedDomains(ctx context.Context) error {
+	return bot.RemoveMessengerProfile(ctx, MessengerProfileFieldWhitelistedDomains)
+}
+
+// Set the url opened by the log in button of the account linking flow
+// https://developers.facebook.com/docs/messenger-platform/reference/messenger-profile-api/account-linking-url
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		accountLinkingURL: a https url
+// Output:
+// 		An error if exists
+func (bot *Bot) SetAccountLinkingURL(ctx context.Context, accountLinkingURL string) error {
+	return bot.SetMessengerProfile(ctx, MessengerProfile{AccountLinkingURL: accountLinkingURL})
+}
+
+// Remove account linking url from the page
+// https://developers.facebook.com/docs/messenger-platform/reference/messenger-profile-api/#delete
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// Output:
+// 		An error if exists
+func (bot *Bot) RemoveAccountLinkingURL(ctx context.Context) error {
+	return bot.RemoveMessengerProfile(ctx, MessengerProfileFieldAccountLinkingURL)
+}
+
+// Set the chat extension opened from the home url of the page
+// https://developers.facebook.com/docs/messenger-platform/reference/messenger-profile-api/home-url
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		homeURL: a HomeURL object, its url must be whitelisted
+// Output:
+// 		An error if exists
+func (bot *Bot) SetHomeURL(ctx context.Context, homeURL HomeURL) error {
+	return bot.SetMessengerProfile(ctx, MessengerProfile{HomeURL: &homeURL})
+}
+
+// Remove home url from the page
+// https://developers.facebook.com/docs/messenger-platform/reference/messenger-profile-api/#delete
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// Output:
+// 		An error if exists
+func (bot *Bot) RemoveHomeURL(ctx context.Context) error {
+	return bot.RemoveMessengerProfile(ctx, MessengerProfileFieldHomeURL)
+}
+
+func messengerProfileFieldNames(fields []MessengerProfileField) []string {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = string(field)
+	}
+	return names
+}
@@ -0,0 +1,224 @@
+package messenger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+type MessengerProfileChangeAction string
+
+const (
+	MessengerProfileChangeAdd    = MessengerProfileChangeAction("add")
+	MessengerProfileChangeUpdate = MessengerProfileChangeAction("update")
+	MessengerProfileChangeRemove = MessengerProfileChangeAction("remove")
+)
+
+// MessengerProfileChange is the difference of one field between the live and the desired Messenger profile.
+// Current and Desired are the canonical JSON values of the field, nil when the field is not set.
+type MessengerProfileChange struct {
+	Field   MessengerProfileField
+	Action  MessengerProfileChangeAction
+	Current json.RawMessage
+	Desired json.RawMessage
+}
+
+// SyncOptions configures SyncMessengerProfile.
+type SyncOptions struct {
+	DryRun bool      // only compute and print the changes, do not apply them
+	Out    io.Writer // where the changes are printed, nil prints nothing
+}
+
+// Read a MessengerProfile from JSON, using the field names of the Messenger Profile API.
+//
+// Input:
+// 		r: reader of the JSON document
+// Output:
+// 		The MessengerProfile and an error if exists
+func LoadMessengerProfile(r io.Reader) (*MessengerProfile, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	var profile MessengerProfile
+	if err := decoder.Decode(&profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// Read a MessengerProfile from a JSON file, using the field names of the Messenger Profile API.
+//
+// Input:
+// 		path: path of the JSON file
+// Output:
+// 		The MessengerProfile and an error if exists
+func LoadMessengerProfileFile(path string) (*MessengerProfile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return LoadMessengerProfile(f)
+}
+
+// Compute the field-level changes which turn the current profile into the desired one.
+// The desired profile is complete: a field it does not set is removed from the current profile.
+//
+// Input:
+// 		current: the live profile, e.g. from GetMessengerProfile
+// 		desired: the profile to reach
+// Output:
+// 		The changes, in the order of AllMessengerProfileFields, and an error if exists
+func DiffMessengerProfile(current MessengerProfile, desired MessengerProfile) ([]MessengerProfileChange, error) {
+	currentFields, err := messengerProfileFields(current)
+	if err != nil {
+		return nil, err
+	}
+	desiredFields, err := messengerProfileFields(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []MessengerProfileChange
+	for _, field := range AllMessengerProfileFields {
+		currentValue, desiredValue := currentFields[field], desiredFields[field]
+		change := MessengerProfileChange{Field: field, Current: currentValue, Desired: desiredValue}
+		switch {
+		case currentValue == nil && desiredValue == nil:
+			continue
+		case currentValue == nil:
+			change.Action = MessengerProfileChangeAdd
+		case desiredValue == nil:
+			change.Action = MessengerProfileChangeRemove
+		case bytes.Equal(currentValue, desiredValue):
+			continue
+		default:
+			change.Action = MessengerProfileChangeUpdate
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// Bring the Messenger profile of the page to the desired state: read the live profile, compute the
+// changes with DiffMessengerProfile, print them, then set the added and updated fields in one request
+// and remove the removed fields in another one. Unchanged fields are not sent.
+//
+// Input:
+// 		ctx: context of the requests, cancelling it aborts the sync
+// 		desired: the complete profile to reach, e.g. from LoadMessengerProfileFile
+// 		options: dry run and output of the sync
+// Output:
+// 		The changes, applied unless it is a dry run, and an error if exists
+func (bot *Bot) SyncMessengerProfile(ctx context.Context, desired MessengerProfile, options SyncOptions) ([]MessengerProfileChange, error) {
+	current, err := bot.GetMessengerProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := DiffMessengerProfile(*current, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Out != nil {
+		if err := printMessengerProfileChanges(options.Out, changes, options.DryRun); err != nil {
+			return nil, err
+		}
+	}
+	if options.DryRun || len(changes) == 0 {
+		return changes, nil
+	}
+
+	setFields := make(map[MessengerProfileField]json.RawMessage)
+	var removedFields []MessengerProfileField
+	for _, change := range changes {
+		if change.Action == MessengerProfileChangeRemove {
+			removedFields = append(removedFields, change.Field)
+		} else {
+			setFields[change.Field] = change.Desired
+		}
+	}
+
+	if len(setFields) > 0 {
+		data, err := json.Marshal(setFields)
+		if err != nil {
+			return nil, err
+		}
+		var profile MessengerProfile
+		if err := json.Unmarshal(data, &profile); err != nil {
+			return nil, err
+		}
+		if err := bot.SetMessengerProfile(ctx, profile); err != nil {
+			return nil, err
+		}
+	}
+	if len(removedFields) > 0 {
+		if err := bot.RemoveMessengerProfile(ctx, removedFields...); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+func (c MessengerProfileChange) String() string {
+	switch c.Action {
+	case MessengerProfileChangeAdd:
+		return fmt.Sprintf("+ %s: %s", c.Field, c.Desired)
+	case MessengerProfileChangeRemove:
+		return fmt.Sprintf("- %s: %s", c.Field, c.Current)
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", c.Field, c.Current, c.Desired)
+	}
+}
+
+func printMessengerProfileChanges(w io.Writer, changes []MessengerProfileChange, dryRun bool) error {
+	prefix := ""
+	if dryRun {
+		prefix = "(dry run) "
+	}
+	if len(changes) == 0 {
+		_, err := fmt.Fprintf(w, "%smessenger profile is up to date\n", prefix)
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "%s%d messenger profile field(s) to change\n", prefix, len(changes)); err != nil {
+		return err
+	}
+	for _, change := range changes {
+		if _, err := fmt.Fprintln(w, change.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Split a profile into the canonical JSON value of every field which is set.
+// The values are decoded and encoded again, so the order of object keys does not matter when comparing.
+func messengerProfileFields(profile MessengerProfile) (map[MessengerProfileField]json.RawMessage, error) {
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[MessengerProfileField]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[MessengerProfileField]json.RawMessage, len(raw))
+	for field, value := range raw {
+		var v interface{}
+		if err := json.Unmarshal(value, &v); err != nil {
+			return nil, err
+		}
+		canonical, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		fields[field] = canonical
+	}
+	return fields, nil
+}
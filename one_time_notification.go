@@ -0,0 +1,177 @@
+package messenger
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNoOneTimeNotifToken is returned when no unused one-time notification token is stored for a user and topic.
+var ErrNoOneTimeNotifToken = errors.New("messenger: no one-time notification token available")
+
+// OneTimeNotifToken is the permission, given by a user in an opt-in, to send one message on a topic
+// outside the standard messaging window.
+type OneTimeNotifToken struct {
+	Token      string    `json:"token"`
+	PSID       string    `json:"psid"`
+	Payload    string    `json:"payload"` // payload of the request template, identifies the topic
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// OneTimeNotifStore keeps the one-time notification tokens of the users until they are used.
+// Take must remove the token it returns, so a token can never be used twice.
+// Implementations must be safe for concurrent use.
+type OneTimeNotifStore interface {
+	// Save stores a new token
+	Save(token OneTimeNotifToken) error
+	// Take removes and returns the oldest token of the user for the topic, ok is false when there is none
+	Take(psid string, payload string) (token OneTimeNotifToken, ok bool, err error)
+}
+
+// Create a one-time notification request template, asking the user to be notified once about a topic.
+// https://developers.facebook.com/docs/messenger-platform/send-messages/one-time-notification
+//
+// Input:
+// 		title: title of the request, up to 65 characters
+// 		payload: payload sent back in the opt-in, identifies the topic
+// Output:
+// 		An Attachment to send in a message
+func NewOneTimeNotifRequest(title string, payload string) Attachment {
+	return Attachment{
+		Type: AttachmentTypeTemplate,
+		Payload: AttachmentPayload{
+			TemplateType: TemplateTypeOneTimeNotifReq,
+			Title:        title,
+			Payload:      payload,
+		},
+	}
+}
+
+// Send a one-time notification request to the specified recipient.
+// https://developers.facebook.com/docs/messenger-platform/send-messages/one-time-notification
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		recipientID: recipient id to send to
+// 		title: title of the request, up to 65 characters
+// 		payload: payload sent back in the opt-in, identifies the topic
+// 		options: optional settings of the send, see SendOption
+// Output:
+// 		Response from API and an error if exists
+func (bot *Bot) SendOneTimeNotifRequest(ctx context.Context, recipientID string, title string, payload string, options ...SendOption) (*SendResponse, error) {
+	return bot.SendAttachmentMessage(ctx, recipientID, NewOneTimeNotifRequest(title, payload), options...)
+}
+
+// Send a message with a one-time notification token, the token can not be used again afterwards.
+// https://developers.facebook.com/docs/messenger-platform/send-messages/one-time-notification
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		token: the one_time_notif_token received in the opt-in
+// 		message: a Message object
+// 		options: optional settings of the send, see SendOption
+// Output:
+// 		Response from API and an error if exists
+func (bot *Bot) SendOneTimeNotification(ctx context.Context, token string, message Message, options ...SendOption) (*SendResponse, error) {
+	payload := Payload{
+		Recipient: &Recipient{OneTimeNotifToken: token},
+		Message:   &message,
+	}
+	for _, option := range options {
+		option(&payload)
+	}
+	return bot.SendRawMessage(ctx, payload)
+}
+
+// Take the oldest token of the user for the topic from the store and send a message with it.
+// The token is consumed even when the send fails, as it may have reached Facebook.
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		store: where the tokens are kept
+// 		psid: page-scoped id of the user
+// 		topic: payload of the request template the user opted in
+// 		message: a Message object
+// 		options: optional settings of the send, see SendOption
+// Output:
+// 		Response from API and an error if exists, ErrNoOneTimeNotifToken when the user has no token for the topic
+func (bot *Bot) SendOneTimeNotificationTo(ctx context.Context, store OneTimeNotifStore, psid string, topic string, message Message, options ...SendOption) (*SendResponse, error) {
+	token, ok, err := store.Take(psid, topic)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNoOneTimeNotifToken
+	}
+	return bot.SendOneTimeNotification(ctx, token.Token, message, options...)
+}
+
+// Save the token of a one-time notification opt-in, other entry messages are ignored.
+//
+// Input:
+// 		store: where the tokens are kept
+// 		message: an EntryMessage received from Facebook
+// Output:
+// 		An error of the store if exists
+func SaveOneTimeNotifOptin(store OneTimeNotifStore, message EntryMessage) error {
+	optin := message.Optin
+	if optin == nil || optin.Type != OptinTypeOneTimeNotifReq || optin.OneTimeNotifToken == "" {
+		return nil
+	}
+
+	receivedAt := time.Now()
+	if message.Timestamp > 0 {
+		receivedAt = time.Unix(0, message.Timestamp*int64(time.Millisecond))
+	}
+	return store.Save(OneTimeNotifToken{
+		Token:      optin.OneTimeNotifToken,
+		PSID:       message.Sender.ID,
+		Payload:    optin.Payload,
+		ReceivedAt: receivedAt,
+	})
+}
+
+// MemoryOneTimeNotifStore is a OneTimeNotifStore which keeps the tokens in memory.
+type MemoryOneTimeNotifStore struct {
+	mu     sync.Mutex
+	tokens map[string][]OneTimeNotifToken
+}
+
+// Create a new, empty MemoryOneTimeNotifStore instance.
+func NewMemoryOneTimeNotifStore() *MemoryOneTimeNotifStore {
+	return &MemoryOneTimeNotifStore{tokens: make(map[string][]OneTimeNotifToken)}
+}
+
+func (s *MemoryOneTimeNotifStore) Save(token OneTimeNotifToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := oneTimeNotifKey(token.PSID, token.Payload)
+	for _, saved := range s.tokens[key] {
+		if saved.Token == token.Token {
+			return nil
+		}
+	}
+	s.tokens[key] = append(s.tokens[key], token)
+	return nil
+}
+
+func (s *MemoryOneTimeNotifStore) Take(psid string, payload string) (OneTimeNotifToken, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := oneTimeNotifKey(psid, payload)
+	tokens := s.tokens[key]
+	if len(tokens) == 0 {
+		return OneTimeNotifToken{}, false, nil
+	}
+	if len(tokens) == 1 {
+		delete(s.tokens, key)
+	} else {
+		s.tokens[key] = tokens[1:]
+	}
+	return tokens[0], true, nil
+}
+
+func oneTimeNotifKey(psid string, payload string) string {
+	return psid + "|" + payload
+}
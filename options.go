@@ -0,0 +1,109 @@
+package messenger
+
+import (
+	"net/http"
+	"time"
+)
+
+const kDefaultTimeout = time.Second * 10
+
+var defaultHTTPClient = &http.Client{Timeout: kDefaultTimeout}
+
+// BotOption configures a Bot created by NewBot
+type BotOption func(bot *Bot)
+
+// Use the given http client for every request of the Bot.
+// Use this to share a client between bots, or to set up a proxy or custom TLS settings.
+//
+// Input:
+// 		client: the http client to use, nil keeps the default client
+func WithHTTPClient(client *http.Client) BotOption {
+	return func(bot *Bot) {
+		if client != nil {
+			bot.client = client
+		}
+	}
+}
+
+// Use the given transport for every request of the Bot, e.g. to stub Graph API in tests.
+// The client of the Bot is copied first, so a client passed to WithHTTPClient is not modified.
+//
+// Input:
+// 		transport: the http.RoundTripper to use
+func WithTransport(transport http.RoundTripper) BotOption {
+	return func(bot *Bot) {
+		client := *bot.httpClient()
+		client.Transport = transport
+		bot.client = &client
+	}
+}
+
+// Set the timeout of every request of the Bot, 10 seconds by default.
+// The client of the Bot is copied first, so a client passed to WithHTTPClient is not modified.
+//
+// Input:
+// 		timeout: the timeout, zero means no timeout
+func WithTimeout(timeout time.Duration) BotOption {
+	return func(bot *Bot) {
+		client := *bot.httpClient()
+		client.Timeout = timeout
+		bot.client = &client
+	}
+}
+
+// Send every request of the Bot to the given url instead of Graph API, e.g. a httptest server.
+//
+// Input:
+// 		graphUrl: base url including the api version, e.g. "https://graph.facebook.com/v6.0"
+func WithGraphUrl(graphUrl string) BotOption {
+	return func(bot *Bot) {
+		bot.GraphUrl = graphUrl
+	}
+}
+
+// SendOption configures a single send of the Send API
+type SendOption func(payload *Payload)
+
+// Send the message with the given messaging type, RESPONSE is used when none is given.
+// https://developers.facebook.com/docs/messenger-platform/send-messages#messaging_types
+//
+// Input:
+// 		messagingType: RESPONSE, UPDATE or MESSAGE_TAG
+func WithMessagingType(messagingType MessagingType) SendOption {
+	return func(payload *Payload) {
+		payload.MessagingType = messagingType
+	}
+}
+
+// Send the message as MESSAGE_TAG with the given tag, to reach the user outside the standard messaging window.
+// https://developers.facebook.com/docs/messenger-platform/send-messages/message-tags
+//
+// Input:
+// 		tag: the message tag, e.g. MessageTagConfirmedEventUpdate
+func WithMessageTag(tag MessageTag) SendOption {
+	return func(payload *Payload) {
+		payload.MessagingType = MessagingTypeMessageTag
+		payload.Tag = tag
+	}
+}
+
+// Set the push notification type of the message, REGULAR when none is given.
+//
+// Input:
+// 		notificationType: type of notification, see NotificationType
+func WithNotificationType(notificationType NotificationType) SendOption {
+	return func(payload *Payload) {
+		payload.NotificationType = notificationType
+	}
+}
+
+// Send the message or sender action as a persona, showing its name and profile picture instead of the page.
+// https://developers.facebook.com/docs/messenger-platform/send-messages/personas
+//
+// Input:
+// 		personaID: id of the persona, see CreatePersona
+func WithPersona(personaID string) SendOption {
+	return func(payload *Payload) {
+		payload.PersonaID = personaID
+	}
+}
@@ -0,0 +1,110 @@
+package messenger
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+// Persona is a name and profile picture, e.g. of a human agent, shown instead of the page on the messages
+// sent with WithPersona.
+// https://developers.facebook.com/docs/messenger-platform/send-messages/personas
+type Persona struct {
+	ID                string `json:"id,omitempty"`
+	Name              string `json:"name"`
+	ProfilePictureURL string `json:"profile_picture_url"`
+}
+
+// Create a persona for the page.
+// https://developers.facebook.com/docs/messenger-platform/send-messages/personas#create
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		name: name shown on the messages of the persona
+// 		profilePictureURL: url of the profile picture shown on the messages of the persona
+// Output:
+// 		The id of the new persona and an error if exists
+func (bot *Bot) CreatePersona(ctx context.Context, name string, profilePictureURL string) (string, error) {
+	persona := Persona{
+		Name:              name,
+		ProfilePictureURL: profilePictureURL,
+	}
+	var resp struct {
+		ID string `json:"id"`
+	}
+	if err := bot.sendRaw(ctx, "/me/personas", http.MethodPost, persona, &resp); err != nil {
+		return "", err
+	}
+	return resp.ID, nil
+}
+
+// Get a persona of the page.
+// https://developers.facebook.com/docs/messenger-platform/send-messages/personas#get
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		personaID: id of the persona
+// Output:
+// 		The Persona and an error if exists
+func (bot *Bot) GetPersona(ctx context.Context, personaID string) (*Persona, error) {
+	var persona Persona
+	if err := bot.get(ctx, "/"+url.PathEscape(personaID), nil, &persona); err != nil {
+		return nil, err
+	}
+	return &persona, nil
+}
+
+// List all personas of the page, following every page of the results.
+// https://developers.facebook.com/docs/messenger-platform/send-messages/personas#get_all
+//
+// Input:
+// 		ctx: context of the requests, cancelling it aborts the call
+// Output:
+// 		An array of Persona objects and an error if exists
+func (bot *Bot) ListPersonas(ctx context.Context) ([]Persona, error) {
+	var personas []Persona
+	query := url.Values{}
+	for {
+		var resp struct {
+			Data   []Persona `json:"data"`
+			Paging struct {
+				Cursors struct {
+					After string `json:"after"`
+				} `json:"cursors"`
+				Next string `json:"next"`
+			} `json:"paging"`
+		}
+		if err := bot.get(ctx, "/me/personas", query, &resp); err != nil {
+			return nil, err
+		}
+		personas = append(personas, resp.Data...)
+
+		after := resp.Paging.Cursors.After
+		if resp.Paging.Next == "" || after == "" || after == query.Get("after") {
+			return personas, nil
+		}
+		query.Set("after", after)
+	}
+}
+
+// Delete a persona of the page.
+// https://developers.facebook.com/docs/messenger-platform/send-messages/personas#remove
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		personaID: id of the persona
+// Output:
+// 		An error if exists
+func (bot *Bot) DeletePersona(ctx context.Context, personaID string) error {
+	var resp struct {
+		Success bool `json:"success"`
+	}
+	if err := bot.sendRaw(ctx, "/"+url.PathEscape(personaID), http.MethodDelete, Payload{}, &resp); err != nil {
+		return err
+	}
+	if !resp.Success {
+		return errors.New("messenger: persona was not deleted")
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package messenger
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestCreatePersonaBody(t *testing.T) {
+	var body map[string]interface{}
+	server, _ := newGraphServer(t, func(w http.ResponseWriter, r *http.Request, n int) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("can not decode body: %v", err)
+		}
+		w.Write([]byte(`{"id":"42"}`))
+	})
+	bot := NewBot("token", "", WithGraphUrl(server.URL))
+
+	id, err := bot.CreatePersona(context.Background(), "Jane", "https://example.com/jane.png")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id != "42" {
+		t.Errorf("id = %q, want 42", id)
+	}
+	want := map[string]interface{}{"name": "Jane", "profile_picture_url": "https://example.com/jane.png"}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("body = %v, want %v", body, want)
+	}
+}
@@ -0,0 +1,236 @@
+package messenger
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+const kMaxIdleRecipientBuckets = 1024
+
+// ErrRateLimitExceeded is returned by a send when the RateLimiter of the Bot is configured to fail fast
+// and no token is available at the moment.
+var ErrRateLimitExceeded = errors.New("messenger: client-side rate limit exceeded")
+
+// RateLimitConfig configures a RateLimiter. A zero rate disables the matching limit.
+type RateLimitConfig struct {
+	GlobalRate     float64 // sends per second for the whole page
+	GlobalBurst    int     // sends allowed at once, defaults to the ceiling of GlobalRate
+	RecipientRate  float64 // sends per second to a single recipient
+	RecipientBurst int     // sends allowed at once to a single recipient, defaults to the ceiling of RecipientRate
+	FailFast       bool    // return ErrRateLimitExceeded instead of waiting for a token
+}
+
+// RateLimitUsage is a snapshot of a RateLimiter, useful to pace broadcast jobs.
+type RateLimitUsage struct {
+	GlobalRate      float64 // configured sends per second for the whole page
+	GlobalAvailable float64 // tokens available right now for the whole page, +Inf without a global limit
+	Recipients      int     // recipients with a tracked bucket
+	Waiting         int     // sends waiting for a token right now
+	Allowed         uint64  // sends let through since the limiter was created
+	Rejected        uint64  // sends rejected because of FailFast or a context ending before a token was available
+}
+
+// RateLimiter is a token bucket limiter for Send API calls, applied globally and per recipient.
+// Every attempt of a call retried by the RetryPolicy of the Bot takes a token, so retries never exceed the rate,
+// a retry refused by the limiter ends the call with the error of the last attempt.
+// Install it on a Bot with WithRateLimiter, one limiter can be shared by all bots of a page.
+type RateLimiter struct {
+	config RateLimitConfig
+
+	mu         sync.Mutex
+	global     *tokenBucket
+	recipients map[string]*tokenBucket
+	waiting    int
+	allowed    uint64
+	rejected   uint64
+}
+
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// Create a new RateLimiter instance.
+//
+// Input:
+// 		config: limits of the RateLimiter
+// Output:
+// 		A RateLimiter instance
+func NewRateLimiter(config RateLimitConfig) *RateLimiter {
+	limiter := &RateLimiter{
+		config:     config,
+		recipients: make(map[string]*tokenBucket),
+	}
+	if config.GlobalRate > 0 {
+		limiter.global = newTokenBucket(config.GlobalRate, config.GlobalBurst, time.Now())
+	}
+	return limiter
+}
+
+// Limit the Send API calls of the Bot with the given RateLimiter.
+//
+// Input:
+// 		limiter: the RateLimiter to use, can be shared by several bots
+func WithRateLimiter(limiter *RateLimiter) BotOption {
+	return func(bot *Bot) {
+		bot.rateLimiter = limiter
+	}
+}
+
+// Wait for the rate limiter of the Bot before a send to the recipient, if the Bot has one
+func (bot *Bot) waitRateLimit(ctx context.Context, recipient *Recipient) error {
+	if bot.rateLimiter == nil {
+		return nil
+	}
+	var recipientID string
+	if recipient != nil {
+		recipientID = recipient.ID
+	}
+	return bot.rateLimiter.Wait(ctx, recipientID)
+}
+
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	if burst <= 0 {
+		burst = int(math.Ceil(rate))
+	}
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: now}
+}
+
+// Refill the bucket with the tokens earned since the last call
+func (b *tokenBucket) advance(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+}
+
+// Return how long to wait until the bucket has a whole token
+func (b *tokenBucket) delay() time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// Wait until a send to the recipient is allowed, or fail right away when the limiter is configured to fail fast.
+//
+// Input:
+// 		ctx: context of the send, the wait is aborted when it is done
+// 		recipientID: id of the recipient, an empty id is only limited globally
+// Output:
+// 		ErrRateLimitExceeded when failing fast, the error of ctx when it ends before a token is available
+func (l *RateLimiter) Wait(ctx context.Context, recipientID string) error {
+	l.mu.Lock()
+	now := time.Now()
+	buckets := l.buckets(recipientID, now)
+
+	var delay time.Duration
+	for _, bucket := range buckets {
+		if d := bucket.delay(); d > delay {
+			delay = d
+		}
+	}
+
+	if delay > 0 && l.config.FailFast {
+		l.rejected++
+		l.mu.Unlock()
+		return ErrRateLimitExceeded
+	}
+	if deadline, ok := ctx.Deadline(); ok && now.Add(delay).After(deadline) {
+		l.rejected++
+		l.mu.Unlock()
+		return context.DeadlineExceeded
+	}
+
+	// Reserve the tokens now, so later callers queue up behind this one
+	for _, bucket := range buckets {
+		bucket.tokens--
+	}
+	if delay == 0 {
+		l.allowed++
+		l.mu.Unlock()
+		return nil
+	}
+	l.waiting++
+	l.mu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		l.mu.Lock()
+		l.waiting--
+		l.allowed++
+		l.mu.Unlock()
+		return nil
+	case <-ctx.Done():
+		// Give the reserved tokens back
+		l.mu.Lock()
+		l.waiting--
+		l.rejected++
+		for _, bucket := range buckets {
+			bucket.tokens = math.Min(bucket.burst, bucket.tokens+1)
+		}
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Return the refilled buckets which apply to a send to the recipient, the caller must hold l.mu
+func (l *RateLimiter) buckets(recipientID string, now time.Time) []*tokenBucket {
+	buckets := make([]*tokenBucket, 0, 2)
+	if l.global != nil {
+		l.global.advance(now)
+		buckets = append(buckets, l.global)
+	}
+	if recipientID == "" || l.config.RecipientRate <= 0 {
+		return buckets
+	}
+
+	bucket, ok := l.recipients[recipientID]
+	if !ok {
+		if len(l.recipients) >= kMaxIdleRecipientBuckets {
+			l.pruneRecipients(now)
+		}
+		bucket = newTokenBucket(l.config.RecipientRate, l.config.RecipientBurst, now)
+		l.recipients[recipientID] = bucket
+	}
+	bucket.advance(now)
+	return append(buckets, bucket)
+}
+
+// Forget the recipients whose bucket is full again, a new bucket would be identical
+func (l *RateLimiter) pruneRecipients(now time.Time) {
+	for recipientID, bucket := range l.recipients {
+		bucket.advance(now)
+		if bucket.tokens >= bucket.burst {
+			delete(l.recipients, recipientID)
+		}
+	}
+}
+
+// Return a snapshot of the current usage of the limiter.
+func (l *RateLimiter) Usage() RateLimitUsage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	usage := RateLimitUsage{
+		GlobalRate: l.config.GlobalRate,
+		Recipients: len(l.recipients),
+		Waiting:    l.waiting,
+		Allowed:    l.allowed,
+		Rejected:   l.rejected,
+	}
+	if l.global != nil {
+		l.global.advance(time.Now())
+		usage.GlobalAvailable = l.global.tokens
+	} else {
+		usage.GlobalAvailable = math.Inf(1)
+	}
+	return usage
+}
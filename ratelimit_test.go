@@ -0,0 +1,197 @@
+package messenger
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterBurst(t *testing.T) {
+	limiter := NewRateLimiter(RateLimitConfig{GlobalRate: 1, GlobalBurst: 2, FailFast: true})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := limiter.Wait(ctx, ""); err != nil {
+			t.Fatalf("send %d: unexpected error: %v", i, err)
+		}
+	}
+	if err := limiter.Wait(ctx, ""); err != ErrRateLimitExceeded {
+		t.Fatalf("error = %v, want ErrRateLimitExceeded", err)
+	}
+
+	usage := limiter.Usage()
+	if usage.Allowed != 2 || usage.Rejected != 1 {
+		t.Errorf("allowed = %d, rejected = %d, want 2 and 1", usage.Allowed, usage.Rejected)
+	}
+	if usage.GlobalRate != 1 || usage.GlobalAvailable >= 1 {
+		t.Errorf("global rate = %v, available = %v, want 1 and less than a token", usage.GlobalRate, usage.GlobalAvailable)
+	}
+}
+
+func TestRateLimiterRefill(t *testing.T) {
+	limiter := NewRateLimiter(RateLimitConfig{GlobalRate: 20, GlobalBurst: 1})
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(ctx, ""); err != nil {
+			t.Fatalf("send %d: unexpected error: %v", i, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("3 sends at 20/s with a burst of 1 took %v, want about 100ms", elapsed)
+	}
+}
+
+func TestRateLimiterPerRecipient(t *testing.T) {
+	limiter := NewRateLimiter(RateLimitConfig{RecipientRate: 1, FailFast: true})
+	ctx := context.Background()
+
+	if err := limiter.Wait(ctx, "a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := limiter.Wait(ctx, "a"); err != ErrRateLimitExceeded {
+		t.Fatalf("second send to a: error = %v, want ErrRateLimitExceeded", err)
+	}
+	if err := limiter.Wait(ctx, "b"); err != nil {
+		t.Fatalf("send to b: unexpected error: %v", err)
+	}
+	if err := limiter.Wait(ctx, ""); err != nil {
+		t.Fatalf("send without recipient: unexpected error: %v", err)
+	}
+
+	usage := limiter.Usage()
+	if usage.Recipients != 2 {
+		t.Errorf("recipients = %d, want 2", usage.Recipients)
+	}
+	if !math.IsInf(usage.GlobalAvailable, 1) {
+		t.Errorf("global available = %v, want +Inf without a global limit", usage.GlobalAvailable)
+	}
+}
+
+func TestRateLimiterPruneRecipients(t *testing.T) {
+	limiter := NewRateLimiter(RateLimitConfig{RecipientRate: 1000, RecipientBurst: 1})
+	ctx := context.Background()
+
+	for i := 0; i < kMaxIdleRecipientBuckets-1; i++ {
+		if err := limiter.Wait(ctx, strconv.Itoa(i)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// Let the idle buckets fill up again, then empty the bucket of a busy recipient
+	time.Sleep(10 * time.Millisecond)
+	if err := limiter.Wait(ctx, "busy"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := limiter.Usage().Recipients; n != kMaxIdleRecipientBuckets {
+		t.Fatalf("recipients = %d, want %d", n, kMaxIdleRecipientBuckets)
+	}
+
+	if err := limiter.Wait(ctx, "new"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := limiter.Usage().Recipients; n != 2 {
+		t.Errorf("recipients after pruning = %d, want 2, the busy and the new recipient", n)
+	}
+}
+
+func TestRateLimiterRefundOnCancel(t *testing.T) {
+	limiter := NewRateLimiter(RateLimitConfig{GlobalRate: 1, GlobalBurst: 1})
+	if err := limiter.Wait(context.Background(), ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- limiter.Wait(ctx, "")
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for limiter.Usage().Waiting != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("send is not waiting for a token")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+
+	usage := limiter.Usage()
+	if usage.Waiting != 0 || usage.Allowed != 1 || usage.Rejected != 1 {
+		t.Errorf("waiting = %d, allowed = %d, rejected = %d, want 0, 1 and 1", usage.Waiting, usage.Allowed, usage.Rejected)
+	}
+	// The reserved token is given back, so the bucket is not in debt
+	if usage.GlobalAvailable < 0 {
+		t.Errorf("global available = %v, want the reserved token refunded", usage.GlobalAvailable)
+	}
+}
+
+func TestRateLimiterDeadline(t *testing.T) {
+	limiter := NewRateLimiter(RateLimitConfig{GlobalRate: 1, GlobalBurst: 1})
+	if err := limiter.Wait(context.Background(), ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx, ""); err != context.DeadlineExceeded {
+		t.Fatalf("error = %v, want context.DeadlineExceeded", err)
+	}
+	// The send fails before the deadline without reserving a token
+	if ctx.Err() != nil {
+		t.Error("send waited for the deadline, want it to fail right away")
+	}
+	if available := limiter.Usage().GlobalAvailable; available < 0 {
+		t.Errorf("global available = %v, want no token reserved", available)
+	}
+}
+
+func TestRateLimiterRetryTakesToken(t *testing.T) {
+	server, attempts := newGraphServer(t, func(w http.ResponseWriter, r *http.Request, n int) {
+		if n == 1 {
+			writeGraphError(w, http.StatusInternalServerError, ErrorCodeUnknown)
+			return
+		}
+		writeSendResponse(w)
+	})
+	limiter := NewRateLimiter(RateLimitConfig{GlobalRate: 20, GlobalBurst: 1})
+	bot := NewBot("token", "", WithGraphUrl(server.URL), WithRetryPolicy(testRetryPolicy), WithRateLimiter(limiter))
+
+	if _, err := bot.SendTextMessage(context.Background(), "1", "hi"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := atomic.LoadInt32(attempts); n != 2 {
+		t.Errorf("attempts = %d, want 2", n)
+	}
+	if allowed := limiter.Usage().Allowed; allowed != 2 {
+		t.Errorf("allowed = %d, want 2, one token per attempt", allowed)
+	}
+}
+
+func TestRateLimiterFailFastRetryKeepsError(t *testing.T) {
+	server, attempts := newGraphServer(t, func(w http.ResponseWriter, r *http.Request, n int) {
+		writeGraphError(w, http.StatusInternalServerError, ErrorCodeUnknown)
+	})
+	limiter := NewRateLimiter(RateLimitConfig{GlobalRate: 1, GlobalBurst: 1, FailFast: true})
+	bot := NewBot("token", "", WithGraphUrl(server.URL), WithRetryPolicy(testRetryPolicy), WithRateLimiter(limiter))
+
+	_, err := bot.SendTextMessage(context.Background(), "1", "hi")
+	var graphErr *GraphError
+	if !errors.As(err, &graphErr) || graphErr.Code != ErrorCodeUnknown {
+		t.Fatalf("error = %v, want the GraphError of the first attempt", err)
+	}
+	if n := atomic.LoadInt32(attempts); n != 1 {
+		t.Errorf("attempts = %d, want 1", n)
+	}
+	if rejected := limiter.Usage().Rejected; rejected != 1 {
+		t.Errorf("rejected = %d, want 1", rejected)
+	}
+}
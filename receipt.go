@@ -0,0 +1,205 @@
+package messenger
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Maximum number of elements of a receipt
+const receiptMaxElements = 100
+
+type (
+	// ReceiptTemplate is an order confirmation, with the items, the address and the payment summary.
+	// https://developers.facebook.com/docs/messenger-platform/reference/templates/receipt
+	ReceiptTemplate struct {
+		RecipientName string // required
+		MerchantName  string
+		OrderNumber   string // required, must be unique
+		Currency      string // required, ISO 4217 code, e.g. "USD"
+		PaymentMethod string // required, e.g. "Visa 2345"
+		OrderURL      string
+		Timestamp     time.Time // time of the order, not sent when zero
+		Sharable      bool
+		Elements      []ReceiptElement // up to 100 elements
+		Address       *ReceiptAddress
+		Summary       ReceiptSummary
+		Adjustments   []ReceiptAdjustment
+	}
+
+	// ReceiptElement is an item of a receipt, Price is the price of all the items of the line.
+	ReceiptElement struct {
+		Title    string // required
+		Subtitle string
+		Quantity int
+		Price    float64 // 0 is allowed for free items
+		Currency string
+		ImageURL string
+	}
+
+	ReceiptAddress struct {
+		Street1    string `json:"street_1"`
+		Street2    string `json:"street_2,omitempty"`
+		City       string `json:"city"`
+		PostalCode string `json:"postal_code"`
+		State      string `json:"state"`
+		Country    string `json:"country"`
+	}
+
+	// ReceiptSummary is the payment summary of a receipt, only TotalCost is required.
+	// TotalCost must equal Subtotal + ShippingCost + TotalTax minus the adjustments, the sum of the element
+	// prices stands for Subtotal when it is not set, a receipt with only a total and no element is not checked.
+	ReceiptSummary struct {
+		Subtotal     *float64 `json:"subtotal,omitempty"`
+		ShippingCost *float64 `json:"shipping_cost,omitempty"`
+		TotalTax     *float64 `json:"total_tax,omitempty"`
+		TotalCost    float64  `json:"total_cost"`
+	}
+
+	// ReceiptAdjustment is a discount of a receipt, e.g. a coupon, Amount is subtracted from the total.
+	ReceiptAdjustment struct {
+		Name   string  `json:"name"`
+		Amount float64 `json:"amount"`
+	}
+)
+
+// Convert the receipt to an Attachment to send in a message.
+func (r ReceiptTemplate) Attachment() Attachment {
+	var elements []Element
+	for _, element := range r.Elements {
+		price := element.Price
+		elements = append(elements, Element{
+			Title:    element.Title,
+			Subtitle: element.Subtitle,
+			ImageURL: element.ImageURL,
+			Quantity: element.Quantity,
+			Price:    &price,
+			Currency: element.Currency,
+		})
+	}
+
+	var timestamp string
+	if !r.Timestamp.IsZero() {
+		timestamp = strconv.FormatInt(r.Timestamp.Unix(), 10)
+	}
+	summary := r.Summary
+
+	return Attachment{
+		Type: AttachmentTypeTemplate,
+		Payload: AttachmentPayload{
+			TemplateType:  TemplateTypeReceipt,
+			RecipientName: r.RecipientName,
+			MerchantName:  r.MerchantName,
+			OrderNumber:   r.OrderNumber,
+			Currency:      r.Currency,
+			PaymentMethod: r.PaymentMethod,
+			OrderURL:      r.OrderURL,
+			Timestamp:     timestamp,
+			Sharable:      r.Sharable,
+			Elements:      elements,
+			Address:       r.Address,
+			Summary:       &summary,
+			Adjustments:   r.Adjustments,
+		},
+	}
+}
+
+// Check the receipt against the rules of the receipt template: the required fields, the number of
+// elements and the reconciliation of the summary, to the cent.
+//
+// Output:
+// 		ValidationErrors if the receipt is invalid, nil otherwise
+func (r ReceiptTemplate) Validate() error {
+	var errs ValidationErrors
+	errs.required("recipient_name", r.RecipientName)
+	errs.required("order_number", r.OrderNumber)
+	errs.required("currency", r.Currency)
+	errs.required("payment_method", r.PaymentMethod)
+	errs.maxItems("elements", len(r.Elements), receiptMaxElements)
+
+	for i, element := range r.Elements {
+		field := indexField("elements", i)
+		errs.required(field+".title", element.Title)
+		if element.Price < 0 {
+			errs.add(field+".price", "must not be negative")
+		}
+		if element.Quantity < 0 {
+			errs.add(field+".quantity", "must not be negative")
+		}
+	}
+
+	if r.Address != nil {
+		errs.required("address.street_1", r.Address.Street1)
+		errs.required("address.city", r.Address.City)
+		errs.required("address.postal_code", r.Address.PostalCode)
+		errs.required("address.state", r.Address.State)
+		errs.required("address.country", r.Address.Country)
+	}
+
+	for i, adjustment := range r.Adjustments {
+		errs.required(indexField("adjustments", i)+".name", adjustment.Name)
+	}
+
+	if r.Summary.TotalCost < 0 {
+		errs.add("summary.total_cost", "must not be negative")
+	}
+	r.validateTotals(&errs)
+	return errs.err()
+}
+
+// Send a receipt to the specified recipient, the receipt is validated before it is sent.
+// https://developers.facebook.com/docs/messenger-platform/reference/templates/receipt
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		recipientID: recipient id to send to
+// 		receipt: a ReceiptTemplate object
+// 		options: optional settings of the send, see SendOption
+// Output:
+// 		Response from API and an error if exists, ValidationErrors if the receipt is invalid
+func (bot *Bot) SendReceipt(ctx context.Context, recipientID string, receipt ReceiptTemplate, options ...SendOption) (*SendResponse, error) {
+	if err := receipt.Validate(); err != nil {
+		return nil, err
+	}
+	return bot.SendAttachmentMessage(ctx, recipientID, receipt.Attachment(), options...)
+}
+
+// Check that the parts of the summary add up to the total cost. Without a subtotal, the sum of the element
+// prices stands for it, a receipt with neither a subtotal nor elements is only checked when it has other parts
+func (r ReceiptTemplate) validateTotals(errs *ValidationErrors) {
+	var expected float64
+	subtotalName := "subtotal"
+	switch {
+	case r.Summary.Subtotal != nil:
+		expected = *r.Summary.Subtotal
+	case len(r.Elements) > 0:
+		for _, element := range r.Elements {
+			expected += element.Price
+		}
+		subtotalName = "element prices"
+	case r.Summary.ShippingCost != nil || r.Summary.TotalTax != nil || len(r.Adjustments) > 0:
+		errs.add("summary.subtotal", "is required with shipping_cost, total_tax or adjustments")
+		return
+	default:
+		return
+	}
+
+	if r.Summary.ShippingCost != nil {
+		expected += *r.Summary.ShippingCost
+	}
+	if r.Summary.TotalTax != nil {
+		expected += *r.Summary.TotalTax
+	}
+	for _, adjustment := range r.Adjustments {
+		expected -= adjustment.Amount
+	}
+	if toCents(expected) != toCents(r.Summary.TotalCost) {
+		errs.add("summary.total_cost", "is %.2f, %s, shipping cost, tax and adjustments give %.2f",
+			r.Summary.TotalCost, subtotalName, expected)
+	}
+}
+
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
@@ -0,0 +1,64 @@
+package messenger
+
+import (
+	"errors"
+	"testing"
+)
+
+func amount(v float64) *float64 {
+	return &v
+}
+
+func TestReceiptValidateTotals(t *testing.T) {
+	items := []ReceiptElement{{Title: "a", Price: 50}, {Title: "b", Price: 25}}
+
+	tests := []struct {
+		name        string
+		elements    []ReceiptElement
+		summary     ReceiptSummary
+		adjustments []ReceiptAdjustment
+		field       string // field of the expected error, empty for a valid receipt
+	}{
+		{"subtotal matches", items, ReceiptSummary{Subtotal: amount(75), TotalCost: 75}, nil, ""},
+		{"subtotal with every part", items, ReceiptSummary{Subtotal: amount(75), ShippingCost: amount(4.95), TotalTax: amount(6.19), TotalCost: 76.14},
+			[]ReceiptAdjustment{{Name: "coupon", Amount: 10}}, ""},
+		{"subtotal wins over element prices", items, ReceiptSummary{Subtotal: amount(60), TotalCost: 60}, nil, ""},
+		{"subtotal mismatch", items, ReceiptSummary{Subtotal: amount(75), TotalCost: 70}, nil, "summary.total_cost"},
+		{"rounded to the cent", nil, ReceiptSummary{Subtotal: amount(0.1), ShippingCost: amount(0.2), TotalCost: 0.3}, nil, ""},
+		{"element prices match", items, ReceiptSummary{TotalCost: 75}, nil, ""},
+		{"element prices mismatch", items, ReceiptSummary{TotalCost: 10}, nil, "summary.total_cost"},
+		{"element prices mismatch with zero shipping", items, ReceiptSummary{ShippingCost: amount(0), TotalCost: 10}, nil, "summary.total_cost"},
+		{"element prices with parts", items, ReceiptSummary{ShippingCost: amount(5), TotalCost: 70},
+			[]ReceiptAdjustment{{Name: "coupon", Amount: 10}}, ""},
+		{"only a total", nil, ReceiptSummary{TotalCost: 10}, nil, ""},
+		{"parts without subtotal nor elements", nil, ReceiptSummary{TotalTax: amount(1), TotalCost: 10}, nil, "summary.subtotal"},
+		{"adjustments without subtotal nor elements", nil, ReceiptSummary{TotalCost: 10},
+			[]ReceiptAdjustment{{Name: "coupon", Amount: 10}}, "summary.subtotal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipt := ReceiptTemplate{
+				RecipientName: "Jane Doe",
+				OrderNumber:   "1",
+				Currency:      "USD",
+				PaymentMethod: "Visa 2345",
+				Elements:      tt.elements,
+				Summary:       tt.summary,
+				Adjustments:   tt.adjustments,
+			}
+
+			err := receipt.Validate()
+			if tt.field == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var errs ValidationErrors
+			if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != tt.field {
+				t.Fatalf("error = %v, want a single ValidationError on %s", err, tt.field)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package messenger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidRecipient is returned by a send whose Recipient does not set exactly one identifier.
+var ErrInvalidRecipient = errors.New("messenger: invalid recipient")
+
+// Check that exactly one identifier of the recipient is set, and that Name is only given with PhoneNumber.
+//
+// Output:
+// 		An error wrapping ErrInvalidRecipient if the recipient is invalid
+func (r Recipient) Validate() error {
+	var set []string
+	for _, identifier := range []struct {
+		name  string
+		value string
+	}{
+		{"id", r.ID},
+		{"user_ref", r.UserRef},
+		{"phone_number", r.PhoneNumber},
+		{"post_id", r.PostID},
+		{"comment_id", r.CommentID},
+		{"one_time_notif_token", r.OneTimeNotifToken},
+		{"notification_messages_token", r.NotificationMessagesToken},
+	} {
+		if identifier.value != "" {
+			set = append(set, identifier.name)
+		}
+	}
+
+	switch {
+	case len(set) == 0:
+		return fmt.Errorf("%w: no identifier is set", ErrInvalidRecipient)
+	case len(set) > 1:
+		return fmt.Errorf("%w: only one identifier can be set, got %s", ErrInvalidRecipient, strings.Join(set, ", "))
+	case r.Name != nil && r.PhoneNumber == "":
+		return fmt.Errorf("%w: name can only be set with phone_number", ErrInvalidRecipient)
+	}
+	return nil
+}
+
+// Send message to any kind of recipient.
+// https://developers.facebook.com/docs/messenger-platform/reference/send-api/#recipient
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		recipient: a Recipient object with exactly one identifier
+// 		message: a Message object
+// 		options: optional settings of the send, see SendOption
+// Output:
+// 		Response from API and an error if exists
+func (bot *Bot) SendMessageTo(ctx context.Context, recipient Recipient, message Message, options ...SendOption) (*SendResponse, error) {
+	payload := Payload{
+		Recipient: &recipient,
+		Message:   &message,
+	}
+	for _, option := range options {
+		option(&payload)
+	}
+	return bot.SendRawMessage(ctx, payload)
+}
+
+// Send message to a user who opted in through the checkbox plugin.
+// https://developers.facebook.com/docs/messenger-platform/discovery/checkbox-plugin
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		userRef: the user_ref of the checkbox plugin, received in the opt-in
+// 		message: a Message object
+// 		options: optional settings of the send, see SendOption
+// Output:
+// 		Response from API and an error if exists
+func (bot *Bot) SendToUserRef(ctx context.Context, userRef string, message Message, options ...SendOption) (*SendResponse, error) {
+	return bot.SendMessageTo(ctx, Recipient{UserRef: userRef}, message, options...)
+}
+
+// Send message to a phone number, using customer matching.
+// https://developers.facebook.com/docs/messenger-platform/identity/customer-matching
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		phoneNumber: phone number of the user, in the format +1(212)555-2368
+// 		name: optional name of the user, improves the match
+// 		message: a Message object
+// 		options: optional settings of the send, see SendOption
+// Output:
+// 		Response from API and an error if exists
+func (bot *Bot) SendToPhoneNumber(ctx context.Context, phoneNumber string, name *RecipientName, message Message, options ...SendOption) (*SendResponse, error) {
+	return bot.SendMessageTo(ctx, Recipient{PhoneNumber: phoneNumber, Name: name}, message, options...)
+}
+
+// Send a private reply to the author of a comment on the page.
+// https://developers.facebook.com/docs/messenger-platform/discovery/private-replies
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		commentID: id of the comment
+// 		message: a Message object
+// 		options: optional settings of the send, see SendOption
+// Output:
+// 		Response from API and an error if exists
+func (bot *Bot) SendPrivateReplyToComment(ctx context.Context, commentID string, message Message, options ...SendOption) (*SendResponse, error) {
+	return bot.SendMessageTo(ctx, Recipient{CommentID: commentID}, message, options...)
+}
+
+// Send a private reply to the author of a visitor post on the page.
+// https://developers.facebook.com/docs/messenger-platform/discovery/private-replies
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		postID: id of the post
+// 		message: a Message object
+// 		options: optional settings of the send, see SendOption
+// Output:
+// 		Response from API and an error if exists
+func (bot *Bot) SendPrivateReplyToPost(ctx context.Context, postID string, message Message, options ...SendOption) (*SendResponse, error) {
+	return bot.SendMessageTo(ctx, Recipient{PostID: postID}, message, options...)
+}
@@ -0,0 +1,213 @@
+package messenger
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNoNotificationMessagesToken is returned when no recurring notification token is stored for a user and topic.
+	ErrNoNotificationMessagesToken = errors.New("messenger: no recurring notification token available")
+	// ErrNotificationMessagesTokenExpired is returned by a send to an expired recurring notification token.
+	ErrNotificationMessagesTokenExpired = errors.New("messenger: recurring notification token expired")
+)
+
+// NotificationMessagesRequest is a recurring notification request template, asking the user to receive
+// regular messages about a topic.
+// https://developers.facebook.com/docs/messenger-platform/send-messages/recurring-notifications
+type NotificationMessagesRequest struct {
+	Title     string                        // title of the request, up to 65 characters
+	ImageURL  string                        // optional image shown above the title
+	Payload   string                        // payload sent back in the opt-in, identifies the topic
+	Frequency NotificationMessagesFrequency // how often the user agrees to be messaged
+	Reoptin   bool                          // ask the user to opt in again when the token is about to expire
+	Timezone  string                        // optional timezone of the user, e.g. "America/New_York"
+	CTAText   string                        // optional text of the button, e.g. "ALLOW", "GET", "OPT_IN" or "SIGN_UP"
+}
+
+// NotificationMessagesToken is the permission, given by a user in an opt-in, to send messages on a topic
+// at the agreed frequency until the token expires.
+type NotificationMessagesToken struct {
+	Token     string                        `json:"token"`
+	PSID      string                        `json:"psid"`
+	Payload   string                        `json:"payload"` // payload of the request template, identifies the topic
+	Frequency NotificationMessagesFrequency `json:"frequency"`
+	Timezone  string                        `json:"timezone,omitempty"`
+	ExpiresAt time.Time                     `json:"expires_at"`
+}
+
+// NotificationMessagesStore keeps the active recurring notification tokens, one per user and topic.
+// Implementations must be safe for concurrent use.
+type NotificationMessagesStore interface {
+	// Save stores a token, replacing the token of the user for the same topic
+	Save(token NotificationMessagesToken) error
+	// Get returns the token of the user for the topic, ok is false when there is none
+	Get(psid string, payload string) (token NotificationMessagesToken, ok bool, err error)
+	// Delete forgets the token of the user for the topic
+	Delete(psid string, payload string) error
+}
+
+// Convert the request to an Attachment to send in a message.
+func (r NotificationMessagesRequest) Attachment() Attachment {
+	reoptin := NotificationMessagesReoptin("")
+	if r.Reoptin {
+		reoptin = NotificationMessagesReoptinEnabled
+	}
+	return Attachment{
+		Type: AttachmentTypeTemplate,
+		Payload: AttachmentPayload{
+			TemplateType:                  TemplateTypeNotificationMessages,
+			Title:                         r.Title,
+			ImageURL:                      r.ImageURL,
+			Payload:                       r.Payload,
+			NotificationMessagesFrequency: r.Frequency,
+			NotificationMessagesReoptin:   reoptin,
+			NotificationMessagesTimezone:  r.Timezone,
+			NotificationMessagesCTAText:   r.CTAText,
+		},
+	}
+}
+
+// Report whether the token can still be used at the given time.
+func (t NotificationMessagesToken) Expired(now time.Time) bool {
+	return !t.ExpiresAt.IsZero() && !now.Before(t.ExpiresAt)
+}
+
+// Send a recurring notification request to the specified recipient.
+// https://developers.facebook.com/docs/messenger-platform/send-messages/recurring-notifications
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		recipientID: recipient id to send to
+// 		request: a NotificationMessagesRequest object
+// 		options: optional settings of the send, see SendOption
+// Output:
+// 		Response from API and an error if exists
+func (bot *Bot) SendNotificationMessagesRequest(ctx context.Context, recipientID string, request NotificationMessagesRequest, options ...SendOption) (*SendResponse, error) {
+	return bot.SendAttachmentMessage(ctx, recipientID, request.Attachment(), options...)
+}
+
+// Send a message with a recurring notification token, an expired token is refused without calling the API.
+// https://developers.facebook.com/docs/messenger-platform/send-messages/recurring-notifications
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		token: the token received in the opt-in
+// 		message: a Message object
+// 		options: optional settings of the send, see SendOption
+// Output:
+// 		Response from API and an error if exists, ErrNotificationMessagesTokenExpired when the token expired
+func (bot *Bot) SendNotificationMessage(ctx context.Context, token NotificationMessagesToken, message Message, options ...SendOption) (*SendResponse, error) {
+	if token.Expired(time.Now()) {
+		return nil, ErrNotificationMessagesTokenExpired
+	}
+	payload := Payload{
+		Recipient: &Recipient{NotificationMessagesToken: token.Token},
+		Message:   &message,
+	}
+	for _, option := range options {
+		option(&payload)
+	}
+	return bot.SendRawMessage(ctx, payload)
+}
+
+// Send a message with the token of the user for the topic from the store, an expired token is removed from the store.
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		store: where the tokens are kept
+// 		psid: page-scoped id of the user
+// 		topic: payload of the request template the user opted in
+// 		message: a Message object
+// 		options: optional settings of the send, see SendOption
+// Output:
+// 		Response from API and an error if exists, ErrNoNotificationMessagesToken when the user has no token for the topic
+// 		and ErrNotificationMessagesTokenExpired when the token expired
+func (bot *Bot) SendNotificationMessageTo(ctx context.Context, store NotificationMessagesStore, psid string, topic string, message Message, options ...SendOption) (*SendResponse, error) {
+	token, ok, err := store.Get(psid, topic)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNoNotificationMessagesToken
+	}
+	if token.Expired(time.Now()) {
+		if err := store.Delete(psid, topic); err != nil {
+			return nil, err
+		}
+		return nil, ErrNotificationMessagesTokenExpired
+	}
+	return bot.SendNotificationMessage(ctx, token, message, options...)
+}
+
+// Update the store from a recurring notification opt-in: a new or resumed token is saved, a stopped one
+// is removed. Other entry messages are ignored.
+//
+// Input:
+// 		store: where the tokens are kept
+// 		message: an EntryMessage received from Facebook
+// Output:
+// 		An error of the store if exists
+func SaveNotificationMessagesOptin(store NotificationMessagesStore, message EntryMessage) error {
+	optin := message.Optin
+	if optin == nil || optin.Type != OptinTypeNotificationMessages {
+		return nil
+	}
+	if optin.NotificationMessagesStatus == NotificationMessagesStatusStop {
+		return store.Delete(message.Sender.ID, optin.Payload)
+	}
+	if optin.NotificationMessagesToken == "" {
+		return nil
+	}
+
+	var expiresAt time.Time
+	if optin.TokenExpiryTimestamp > 0 {
+		expiresAt = time.Unix(0, optin.TokenExpiryTimestamp*int64(time.Millisecond))
+	}
+	return store.Save(NotificationMessagesToken{
+		Token:     optin.NotificationMessagesToken,
+		PSID:      message.Sender.ID,
+		Payload:   optin.Payload,
+		Frequency: optin.NotificationMessagesFrequency,
+		Timezone:  optin.NotificationMessagesTimezone,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// MemoryNotificationMessagesStore is a NotificationMessagesStore which keeps the tokens in memory.
+type MemoryNotificationMessagesStore struct {
+	mu     sync.RWMutex
+	tokens map[string]NotificationMessagesToken
+}
+
+// Create a new, empty MemoryNotificationMessagesStore instance.
+func NewMemoryNotificationMessagesStore() *MemoryNotificationMessagesStore {
+	return &MemoryNotificationMessagesStore{tokens: make(map[string]NotificationMessagesToken)}
+}
+
+func (s *MemoryNotificationMessagesStore) Save(token NotificationMessagesToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[notificationMessagesKey(token.PSID, token.Payload)] = token
+	return nil
+}
+
+func (s *MemoryNotificationMessagesStore) Get(psid string, payload string) (NotificationMessagesToken, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	token, ok := s.tokens[notificationMessagesKey(psid, payload)]
+	return token, ok, nil
+}
+
+func (s *MemoryNotificationMessagesStore) Delete(psid string, payload string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, notificationMessagesKey(psid, payload))
+	return nil
+}
+
+func notificationMessagesKey(psid string, payload string) string {
+	return psid + "|" + payload
+}
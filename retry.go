@@ -0,0 +1,113 @@
+package messenger
+
+import (
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryPolicy describes how a Bot retries requests failed with a transient error:
+// a 5xx response, a network error or timeout, or one of the throttling codes 4, 17, 32 and 613.
+// Permanent errors, e.g. an unavailable user or an invalid parameter, are never retried.
+//
+// The delay before the n-th retry is BaseDelay * 2^(n-1), capped by MaxDelay, with a random jitter
+// which keeps it between the half and the full value. A Retry-After header asking for a longer delay wins.
+type RetryPolicy struct {
+	MaxAttempts int           // maximum number of attempts including the first one
+	BaseDelay   time.Duration // delay before the first retry
+	MaxDelay    time.Duration // upper bound of a single delay, zero means no bound
+}
+
+// DefaultRetryPolicy makes up to 4 attempts, waiting about 0.5s, 1s and 2s between them.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+}
+
+var (
+	jitterMu   sync.Mutex
+	jitterRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// Retry requests of the Bot failed with a transient error, see RetryPolicy.
+// Requests are not retried unless this option is given.
+//
+// Input:
+// 		policy: the retry policy, e.g. DefaultRetryPolicy
+func WithRetryPolicy(policy RetryPolicy) BotOption {
+	return func(bot *Bot) {
+		bot.retryPolicy = &policy
+	}
+}
+
+// Return the jittered delay to wait before the retry following the given attempt
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	jitterMu.Lock()
+	jitter := time.Duration(jitterRand.Int63n(int64(delay/2) + 1))
+	jitterMu.Unlock()
+	return delay/2 + jitter
+}
+
+// Report whether a failed request may succeed when it is sent again
+// The cancellation of the caller's context is checked by the caller, an error matching
+// context.DeadlineExceeded here may well be the timeout of the http client
+func isRetryable(err error) bool {
+	var graphErr *GraphError
+	if errors.As(err, &graphErr) {
+		switch {
+		case graphErr.IsUserUnavailable(), graphErr.IsPermissionError(), graphErr.IsTokenExpired(),
+			graphErr.Code == ErrorCodeInvalidParameter:
+			return false
+		case graphErr.IsRateLimited(), graphErr.IsTransient, graphErr.StatusCode >= 500,
+			graphErr.Code == ErrorCodeUnknown, graphErr.Code == ErrorCodeServiceUnavailable:
+			return true
+		}
+		return false
+	}
+
+	// The request did not get a response, e.g. a timeout or a reset connection,
+	// or the client timed out while reading the response body
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return false
+	}
+	return errors.As(urlErr.Err, &netErr) || errors.Is(urlErr.Err, io.EOF) || errors.Is(urlErr.Err, io.ErrUnexpectedEOF)
+}
+
+// Parse a Retry-After header given either in seconds or as a http date
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		if delay := time.Until(date); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
@@ -0,0 +1,130 @@
+package messenger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var testRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+// Start a Graph API stub which answers the n-th request, counted from 1, with respond
+func newGraphServer(t *testing.T, respond func(w http.ResponseWriter, r *http.Request, n int)) (*httptest.Server, *int32) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r, int(atomic.AddInt32(&attempts, 1)))
+	}))
+	t.Cleanup(server.Close)
+	return server, &attempts
+}
+
+func writeGraphError(w http.ResponseWriter, status int, code int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write([]byte(`{"error":{"message":"failed","type":"OAuthException","code":` + strconv.Itoa(code) + `}}`))
+}
+
+func writeSendResponse(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"recipient_id":"1","message_id":"m"}`))
+}
+
+func TestRetryServerErrorThenSuccess(t *testing.T) {
+	server, attempts := newGraphServer(t, func(w http.ResponseWriter, r *http.Request, n int) {
+		if n == 1 {
+			writeGraphError(w, http.StatusInternalServerError, ErrorCodeUnknown)
+			return
+		}
+		writeSendResponse(w)
+	})
+	bot := NewBot("token", "", WithGraphUrl(server.URL), WithRetryPolicy(testRetryPolicy))
+
+	resp, err := bot.SendTextMessage(context.Background(), "1", "hi")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.MessageID != "m" {
+		t.Errorf("message id = %q, want %q", resp.MessageID, "m")
+	}
+	if n := atomic.LoadInt32(attempts); n != 2 {
+		t.Errorf("attempts = %d, want 2", n)
+	}
+}
+
+func TestRetryClientTimeout(t *testing.T) {
+	server, attempts := newGraphServer(t, func(w http.ResponseWriter, r *http.Request, n int) {
+		if n == 1 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		writeSendResponse(w)
+	})
+	bot := NewBot("token", "", WithGraphUrl(server.URL), WithTimeout(50*time.Millisecond), WithRetryPolicy(testRetryPolicy))
+
+	if _, err := bot.SendTextMessage(context.Background(), "1", "hi"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := atomic.LoadInt32(attempts); n != 2 {
+		t.Errorf("attempts = %d, want 2", n)
+	}
+}
+
+func TestRetryAfterWinsOverBackoff(t *testing.T) {
+	server, attempts := newGraphServer(t, func(w http.ResponseWriter, r *http.Request, n int) {
+		if n == 1 {
+			w.Header().Set("Retry-After", "1")
+			writeGraphError(w, http.StatusServiceUnavailable, ErrorCodeServiceUnavailable)
+			return
+		}
+		writeSendResponse(w)
+	})
+	bot := NewBot("token", "", WithGraphUrl(server.URL), WithRetryPolicy(testRetryPolicy))
+
+	start := time.Now()
+	if _, err := bot.SendTextMessage(context.Background(), "1", "hi"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want at least the Retry-After delay of 1s", elapsed)
+	}
+	if n := atomic.LoadInt32(attempts); n != 2 {
+		t.Errorf("attempts = %d, want 2", n)
+	}
+}
+
+func TestRetryPermanentErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		code   int
+	}{
+		{"invalid parameter", http.StatusBadRequest, ErrorCodeInvalidParameter},
+		{"invalid access token", http.StatusUnauthorized, ErrorCodeAccessTokenInvalid},
+		{"user unavailable", http.StatusBadRequest, ErrorCodeUserUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, attempts := newGraphServer(t, func(w http.ResponseWriter, r *http.Request, n int) {
+				writeGraphError(w, tt.status, tt.code)
+			})
+			bot := NewBot("token", "", WithGraphUrl(server.URL), WithRetryPolicy(testRetryPolicy))
+
+			_, err := bot.SendTextMessage(context.Background(), "1", "hi")
+			graphErr, ok := err.(*GraphError)
+			if !ok || graphErr.Code != tt.code {
+				t.Fatalf("error = %v, want a GraphError with code %d", err, tt.code)
+			}
+			if n := atomic.LoadInt32(attempts); n != 1 {
+				t.Errorf("attempts = %d, want 1", n)
+			}
+		})
+	}
+}
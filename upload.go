@@ -0,0 +1,272 @@
+package messenger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const kFileDataField = "filedata"
+
+// Upload an attachment from a reader to the Attachment Upload API, so it can be sent later by its id.
+// https://developers.facebook.com/docs/messenger-platform/reference/attachment-upload-api
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		attachmentType: type of the attachment, image, audio, video or file
+// 		r: content of the attachment
+// 		filename: name of the file, its extension decides the content type
+// Output:
+// 		The reusable attachment id and an error if exists
+func (bot *Bot) UploadAttachment(ctx context.Context, attachmentType AttachmentType, r io.Reader, filename string) (string, error) {
+	if bot.attachmentCache == nil {
+		return bot.uploadAttachment(ctx, attachmentType, r, filename)
+	}
+
+	data, r, err := readAttachment(r)
+	if err != nil {
+		return "", err
+	}
+	return bot.attachmentCache.upload(attachmentContentKey(attachmentType, data), func() (string, error) {
+		return bot.uploadAttachment(ctx, attachmentType, r, filename)
+	})
+}
+
+func (bot *Bot) uploadAttachment(ctx context.Context, attachmentType AttachmentType, r io.Reader, filename string) (string, error) {
+	payload := Payload{
+		Message: &Message{
+			Attachment: &Attachment{
+				Type:    attachmentType,
+				Payload: AttachmentPayload{IsReusable: true},
+			},
+		},
+	}
+
+	var resp SendResponse
+	if err := bot.sendMultipart(ctx, "/me/message_attachments", payload, r, filename, nil, &resp); err != nil {
+		return "", err
+	}
+	return resp.AttachmentID, nil
+}
+
+// Upload a local file to the Attachment Upload API, so it can be sent later by its id.
+// https://developers.facebook.com/docs/messenger-platform/reference/attachment-upload-api
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		attachmentType: type of the attachment, image, audio, video or file
+// 		path: path of the file to upload
+// Output:
+// 		The reusable attachment id and an error if exists
+func (bot *Bot) UploadAttachmentFile(ctx context.Context, attachmentType AttachmentType, path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	return bot.UploadAttachment(ctx, attachmentType, f, filepath.Base(path))
+}
+
+// Upload an attachment from a public url to the Attachment Upload API, so it can be sent later by its id.
+// https://developers.facebook.com/docs/messenger-platform/reference/attachment-upload-api
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		attachmentType: type of the attachment, image, audio, video or file
+// 		attachmentUrl: url of the attachment
+// Output:
+// 		The reusable attachment id and an error if exists
+func (bot *Bot) UploadAttachmentUrl(ctx context.Context, attachmentType AttachmentType, attachmentUrl string) (string, error) {
+	if bot.attachmentCache == nil {
+		return bot.uploadAttachmentUrl(ctx, attachmentType, attachmentUrl)
+	}
+	return bot.attachmentCache.upload(attachmentUrlKey(attachmentType, attachmentUrl), func() (string, error) {
+		return bot.uploadAttachmentUrl(ctx, attachmentType, attachmentUrl)
+	})
+}
+
+func (bot *Bot) uploadAttachmentUrl(ctx context.Context, attachmentType AttachmentType, attachmentUrl string) (string, error) {
+	payload := Payload{
+		Message: &Message{
+			Attachment: &Attachment{
+				Type:    attachmentType,
+				Payload: AttachmentPayload{URL: attachmentUrl, IsReusable: true},
+			},
+		},
+	}
+
+	var resp SendResponse
+	if err := bot.sendRaw(ctx, "/me/message_attachments", http.MethodPost, payload, &resp); err != nil {
+		return "", err
+	}
+	return resp.AttachmentID, nil
+}
+
+// Send an attachment read from a reader to the specified recipient, the attachment is uploaded as reusable
+// and its id is returned in SendResponse.AttachmentID.
+// https://developers.facebook.com/docs/messenger-platform/send-messages#file
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		recipientID: recipient id to send to
+// 		attachmentType: type of the attachment, image, audio, video or file
+// 		r: content of the attachment
+// 		filename: name of the file, its extension decides the content type
+// 		options: optional settings of the send, see SendOption
+// Output:
+// 		Response from API and an error if exists
+func (bot *Bot) SendAttachmentReader(ctx context.Context, recipientID string, attachmentType AttachmentType, r io.Reader, filename string, options ...SendOption) (*SendResponse, error) {
+	if bot.attachmentCache == nil {
+		return bot.sendAttachmentReader(ctx, recipientID, attachmentType, r, filename, options)
+	}
+
+	data, r, err := readAttachment(r)
+	if err != nil {
+		return nil, err
+	}
+	key := attachmentContentKey(attachmentType, data)
+	return bot.attachmentCache.send(ctx, bot, recipientID, attachmentType, key, options, func() (*SendResponse, error) {
+		return bot.sendAttachmentReader(ctx, recipientID, attachmentType, r, filename, options)
+	})
+}
+
+func (bot *Bot) sendAttachmentReader(ctx context.Context, recipientID string, attachmentType AttachmentType, r io.Reader, filename string, options []SendOption) (*SendResponse, error) {
+	payload := Payload{
+		Recipient: &Recipient{ID: recipientID},
+		Message: &Message{
+			Attachment: &Attachment{
+				Type:    attachmentType,
+				Payload: AttachmentPayload{IsReusable: true},
+			},
+		},
+	}
+	for _, option := range options {
+		option(&payload)
+	}
+	if err := bot.prepareSend(ctx, &payload); err != nil {
+		return nil, err
+	}
+	var resp SendResponse
+	if err := bot.sendMultipart(ctx, "/me/messages", payload, r, filename, payload.Recipient, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Send a local file to the specified recipient, the file is uploaded as reusable
+// and its id is returned in SendResponse.AttachmentID.
+// https://developers.facebook.com/docs/messenger-platform/send-messages#file
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		recipientID: recipient id to send to
+// 		attachmentType: type of the attachment, image, audio, video or file
+// 		path: path of the file to send
+// 		options: optional settings of the send, see SendOption
+// Output:
+// 		Response from API and an error if exists
+func (bot *Bot) SendAttachmentFile(ctx context.Context, recipientID string, attachmentType AttachmentType, path string, options ...SendOption) (*SendResponse, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return bot.SendAttachmentReader(ctx, recipientID, attachmentType, f, filepath.Base(path), options...)
+}
+
+// Send an attachment uploaded before to the specified recipient by its id.
+// https://developers.facebook.com/docs/messenger-platform/send-messages/saving-assets
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		recipientID: recipient id to send to
+// 		attachmentType: type of the attachment
+// 		attachmentID: id returned by an upload
+// 		options: optional settings of the send, see SendOption
+// Output:
+// 		Response from API and an error if exists
+func (bot *Bot) SendAttachmentID(ctx context.Context, recipientID string, attachmentType AttachmentType, attachmentID string, options ...SendOption) (*SendResponse, error) {
+	attachment := Attachment{
+		Type:    attachmentType,
+		Payload: AttachmentPayload{AttachmentID: attachmentID},
+	}
+	return bot.SendAttachmentMessage(ctx, recipientID, attachment, options...)
+}
+
+// Send a multipart form with every field of the payload and the file data.
+// The whole form is built in memory, so it can be sent again when the request is retried,
+// recipient is given for a Send API call, see Bot.do.
+func (bot *Bot) sendMultipart(ctx context.Context, requestSubPath string, payload Payload, r io.Reader, filename string,
+	recipient *Recipient, result interface{}) error {
+	body := new(bytes.Buffer)
+	form := multipart.NewWriter(body)
+
+	if err := writePayloadFields(form, payload); err != nil {
+		return err
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(filename))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		kFileDataField, strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(filename)))
+	header.Set("Content-Type", contentType)
+	part, err := form.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, r); err != nil {
+		return err
+	}
+
+	if err := form.Close(); err != nil {
+		return err
+	}
+
+	return bot.do(ctx, http.MethodPost, requestSubPath, form.FormDataContentType(), body.Bytes(), recipient, result)
+}
+
+// Write every top-level field of the payload as a form field,
+// objects and arrays are written as JSON and strings as they are
+func writePayloadFields(form *multipart.Writer, payload Payload) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := string(fields[name])
+		var s string
+		if err := json.Unmarshal(fields[name], &s); err == nil {
+			value = s
+		}
+		if err := form.WriteField(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,253 @@
+package messenger
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type UserProfileField string
+
+const (
+	UserProfileFieldID         = UserProfileField("id")
+	UserProfileFieldName       = UserProfileField("name")
+	UserProfileFieldFirstName  = UserProfileField("first_name")
+	UserProfileFieldLastName   = UserProfileField("last_name")
+	UserProfileFieldProfilePic = UserProfileField("profile_pic")
+	UserProfileFieldLocale     = UserProfileField("locale")
+	UserProfileFieldTimezone   = UserProfileField("timezone")
+	UserProfileFieldGender     = UserProfileField("gender")
+)
+
+// DefaultUserProfileFields are the fields requested by GetUserProfile when no field is given,
+// they are available without any additional permission.
+var DefaultUserProfileFields = []UserProfileField{
+	UserProfileFieldFirstName,
+	UserProfileFieldLastName,
+	UserProfileFieldProfilePic,
+}
+
+// UserProfile is the public profile of a user, only the requested fields are set.
+// https://developers.facebook.com/docs/messenger-platform/identity/user-profile
+type UserProfile struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name,omitempty"`
+	FirstName  string  `json:"first_name,omitempty"`
+	LastName   string  `json:"last_name,omitempty"`
+	ProfilePic string  `json:"profile_pic,omitempty"`
+	Locale     string  `json:"locale,omitempty"`
+	Timezone   float64 `json:"timezone,omitempty"` // offset from UTC in hours, can be fractional
+	Gender     string  `json:"gender,omitempty"`
+}
+
+// Get the profile of a user by the page-scoped id, from the UserProfileCache of the Bot if it has one.
+// https://developers.facebook.com/docs/messenger-platform/identity/user-profile
+//
+// Input:
+// 		ctx: context of the request, cancelling it aborts the call
+// 		psid: page-scoped id of the user
+// 		fields: fields to get, DefaultUserProfileFields if none is given
+// Output:
+// 		The UserProfile and an error if exists
+func (bot *Bot) GetUserProfile(ctx context.Context, psid string, fields ...UserProfileField) (*UserProfile, error) {
+	if len(fields) == 0 {
+		fields = DefaultUserProfileFields
+	}
+	if bot.userProfileCache == nil {
+		return bot.getUserProfile(ctx, psid, fields)
+	}
+	return bot.userProfileCache.get(ctx, psid, fields, func(ctx context.Context) (*UserProfile, error) {
+		return bot.getUserProfile(ctx, psid, fields)
+	})
+}
+
+func (bot *Bot) getUserProfile(ctx context.Context, psid string, fields []UserProfileField) (*UserProfile, error) {
+	query := url.Values{}
+	query.Set("fields", joinUserProfileFields(fields))
+
+	var profile UserProfile
+	if err := bot.get(ctx, "/"+url.PathEscape(psid), query, &profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func joinUserProfileFields(fields []UserProfileField) string {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = string(field)
+	}
+	return strings.Join(names, ",")
+}
+
+// UserProfileCache keeps user profiles in memory for a fixed time to live.
+// Concurrent lookups of the same profile share a single Graph API call, which is not cancelled when one
+// of the callers gives up: it is only bounded by the timeout and the retry policy of the Bot, while every
+// caller stops waiting for it when its own context is done.
+type UserProfileCache struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	entries   map[string]userProfileEntry
+	calls     map[string]*userProfileCall
+	lastPrune time.Time
+}
+
+type userProfileEntry struct {
+	profile UserProfile
+	expires time.Time
+}
+
+type userProfileCall struct {
+	done    chan struct{}
+	profile *UserProfile
+	err     error
+	stale   bool // the profile was invalidated during the call, its result is not cached
+}
+
+// Create a new UserProfileCache instance.
+//
+// Input:
+// 		ttl: how long a profile is kept
+// Output:
+// 		A UserProfileCache instance
+func NewUserProfileCache(ttl time.Duration) *UserProfileCache {
+	return &UserProfileCache{
+		ttl:       ttl,
+		entries:   make(map[string]userProfileEntry),
+		calls:     make(map[string]*userProfileCall),
+		lastPrune: time.Now(),
+	}
+}
+
+// Cache the user profiles got by GetUserProfile, see UserProfileCache.
+//
+// Input:
+// 		cache: the UserProfileCache to use, can be shared by several bots of the same page
+func WithUserProfileCache(cache *UserProfileCache) BotOption {
+	return func(bot *Bot) {
+		bot.userProfileCache = cache
+	}
+}
+
+// Forget every cached profile of the user, e.g. after the user changed the locale.
+// Lookups in progress still return to their callers but their result is not cached,
+// later lookups get the profile from Graph API again.
+//
+// Input:
+// 		psid: page-scoped id of the user
+func (c *UserProfileCache) Invalidate(psid string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if strings.HasPrefix(key, psid+"|") {
+			delete(c.entries, key)
+		}
+	}
+	for key, call := range c.calls {
+		if strings.HasPrefix(key, psid+"|") {
+			call.stale = true
+			delete(c.calls, key)
+		}
+	}
+}
+
+// Return the cached profile, or load it once for all concurrent callers and cache it.
+// The load runs on a context detached from the callers, so one caller giving up does not fail the others,
+// and every caller stops waiting when its own ctx is done.
+func (c *UserProfileCache) get(ctx context.Context, psid string, fields []UserProfileField,
+	load func(ctx context.Context) (*UserProfile, error)) (*UserProfile, error) {
+	key := userProfileKey(psid, fields)
+
+	c.mu.Lock()
+	now := time.Now()
+	if entry, ok := c.entries[key]; ok && now.Before(entry.expires) {
+		c.mu.Unlock()
+		profile := entry.profile
+		return &profile, nil
+	}
+	call, ok := c.calls[key]
+	if !ok {
+		call = &userProfileCall{done: make(chan struct{})}
+		c.calls[key] = call
+		go c.load(detachedContext{ctx}, key, call, load)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return copyUserProfile(call.profile), call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Run a shared load and publish its result, a panic of load is returned as an error to the callers
+func (c *UserProfileCache) load(ctx context.Context, key string, call *userProfileCall,
+	load func(ctx context.Context) (*UserProfile, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			call.profile, call.err = nil, fmt.Errorf("messenger: user profile lookup panicked: %v", r)
+		}
+
+		c.mu.Lock()
+		if !call.stale {
+			delete(c.calls, key)
+		}
+		if call.err == nil && !call.stale {
+			now := time.Now()
+			c.entries[key] = userProfileEntry{profile: *call.profile, expires: now.Add(c.ttl)}
+			c.prune(now)
+		}
+		c.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.profile, call.err = load(ctx)
+}
+
+// Drop the expired profiles at most once per time to live, the caller must hold c.mu
+func (c *UserProfileCache) prune(now time.Time) {
+	if now.Sub(c.lastPrune) < c.ttl {
+		return
+	}
+	for key, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+	c.lastPrune = now
+}
+
+// Return the cache key of a profile, the order of the fields does not matter
+func userProfileKey(psid string, fields []UserProfileField) string {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = string(field)
+	}
+	sort.Strings(names)
+	return psid + "|" + strings.Join(names, ",")
+}
+
+// Copy a shared profile, so callers can not modify each other's value
+func copyUserProfile(profile *UserProfile) *UserProfile {
+	if profile == nil {
+		return nil
+	}
+	p := *profile
+	return &p
+}
+
+// detachedContext keeps the values of its parent but is never cancelled by it
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool)         { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}               { return nil }
+func (detachedContext) Err() error                          { return nil }
+func (c detachedContext) Value(key interface{}) interface{} { return c.parent.Value(key) }
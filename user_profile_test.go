@@ -0,0 +1,94 @@
+package messenger
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Start a Graph API stub answering profile lookups with the first name at the time of the request,
+// blocked until release is closed. Every request is signaled on started
+func newProfileServer(t *testing.T, firstName *atomic.Value, release chan struct{}) (*Bot, *int32, chan struct{}) {
+	started := make(chan struct{}, 4)
+	server, calls := newGraphServer(t, func(w http.ResponseWriter, r *http.Request, n int) {
+		name := firstName.Load().(string)
+		started <- struct{}{}
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"1","first_name":"` + name + `"}`))
+	})
+	bot := NewBot("token", "", WithGraphUrl(server.URL), WithUserProfileCache(NewUserProfileCache(time.Hour)))
+	return bot, calls, started
+}
+
+func TestUserProfileCacheSharedCall(t *testing.T) {
+	var firstName atomic.Value
+	firstName.Store("Jane")
+	release := make(chan struct{})
+	bot, calls, started := newProfileServer(t, &firstName, release)
+
+	// The first caller gives up, the second one still gets the profile of the shared call
+	cancelled, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := bot.GetUserProfile(cancelled, "1")
+		first <- err
+	}()
+	second := make(chan *UserProfile, 1)
+	go func() {
+		profile, _ := bot.GetUserProfile(context.Background(), "1")
+		second <- profile
+	}()
+
+	<-started
+	cancel()
+	if err := <-first; err != context.Canceled {
+		t.Fatalf("first caller: error = %v, want context.Canceled", err)
+	}
+	close(release)
+	if profile := <-second; profile == nil || profile.FirstName != "Jane" {
+		t.Fatalf("second caller: profile = %+v, want Jane", profile)
+	}
+
+	if _, err := bot.GetUserProfile(context.Background(), "1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := atomic.LoadInt32(calls); n != 1 {
+		t.Errorf("graph calls = %d, want 1", n)
+	}
+}
+
+func TestUserProfileCacheInvalidateDuringCall(t *testing.T) {
+	var firstName atomic.Value
+	firstName.Store("Jane")
+	release := make(chan struct{})
+	bot, calls, started := newProfileServer(t, &firstName, release)
+
+	done := make(chan *UserProfile, 1)
+	go func() {
+		profile, _ := bot.GetUserProfile(context.Background(), "1")
+		done <- profile
+	}()
+	<-started
+
+	// The profile changes and is invalidated while the first lookup is in progress
+	bot.userProfileCache.Invalidate("1")
+	firstName.Store("Janet")
+	close(release)
+	if profile := <-done; profile == nil || profile.FirstName != "Jane" {
+		t.Fatalf("in-flight caller: profile = %+v, want Jane", profile)
+	}
+
+	profile, err := bot.GetUserProfile(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if profile.FirstName != "Janet" {
+		t.Errorf("profile after invalidation = %s, want Janet", profile.FirstName)
+	}
+	if n := atomic.LoadInt32(calls); n != 2 {
+		t.Errorf("graph calls = %d, want 2", n)
+	}
+}
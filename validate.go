@@ -0,0 +1,253 @@
+package messenger
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError is a value which breaks a rule of the Messenger Platform, found before it is sent.
+// Field is the path of the value in the JSON payload, e.g. "elements[2].title".
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+// ValidationErrors are all the ValidationError found in a value, use errors.As to read them from an error.
+type ValidationErrors []ValidationError
+
+func (e ValidationError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
+
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return "messenger: invalid value: " + strings.Join(messages, "; ")
+}
+
+// Add a ValidationError for field
+func (errs *ValidationErrors) add(field string, format string, args ...interface{}) {
+	*errs = append(*errs, ValidationError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Check a required string field
+func (errs *ValidationErrors) required(field string, value string) {
+	if value == "" {
+		errs.add(field, "is required")
+	}
+}
+
+// Check the length of a string field, in characters
+func (errs *ValidationErrors) maxLength(field string, value string, max int) {
+	if n := len([]rune(value)); n > max {
+		errs.add(field, "has %d characters, the limit is %d", n, max)
+	}
+}
+
+// Check the number of items of an array field
+func (errs *ValidationErrors) maxItems(field string, n int, max int) {
+	if n > max {
+		errs.add(field, "has %d items, the limit is %d", n, max)
+	}
+}
+
+// Return the errors as an error, nil when there is none
+func (errs ValidationErrors) err() error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Return the path of the item i of an array field
+func indexField(field string, i int) string {
+	return fmt.Sprintf("%s[%d]", field, i)
+}
+
+// Limits of the Messenger Platform
+// https://developers.facebook.com/docs/messenger-platform/reference/send-api
+const (
+	maxTextLength               = 2000
+	maxButtonTemplateTextLength = 640
+	maxQuickReplies             = 13
+	maxQuickReplyTitleLength    = 20
+	maxButtons                  = 3
+	maxButtonTitleLength        = 20
+	maxPayloadLength            = 1000
+	maxElementTitleLength       = 80
+	maxElementSubtitleLength    = 80
+)
+
+// Validate every message before it is sent, a message which breaks a limit of the Messenger Platform
+// is refused with ValidationErrors instead of being rejected by Facebook.
+func WithValidation() BotOption {
+	return func(bot *Bot) {
+		bot.validate = true
+	}
+}
+
+// Check the message against the limits of the Messenger Platform.
+//
+// Output:
+// 		ValidationErrors if the message is invalid, nil otherwise
+func (m Message) Validate() error {
+	var errs ValidationErrors
+	m.validate(&errs, "")
+	return errs.err()
+}
+
+// Check the attachment against the limits of the Messenger Platform.
+//
+// Output:
+// 		ValidationErrors if the attachment is invalid, nil otherwise
+func (a Attachment) Validate() error {
+	var errs ValidationErrors
+	a.validate(&errs, "")
+	return errs.err()
+}
+
+// Check the element against the limits of the Messenger Platform.
+//
+// Output:
+// 		ValidationErrors if the element is invalid, nil otherwise
+func (e Element) Validate() error {
+	var errs ValidationErrors
+	e.validate(&errs, "")
+	return errs.err()
+}
+
+// Check the button against the limits of the Messenger Platform.
+//
+// Output:
+// 		ValidationErrors if the button is invalid, nil otherwise
+func (b Button) Validate() error {
+	var errs ValidationErrors
+	b.validate(&errs, "")
+	return errs.err()
+}
+
+// Check the quick reply against the limits of the Messenger Platform.
+//
+// Output:
+// 		ValidationErrors if the quick reply is invalid, nil otherwise
+func (q QuickReply) Validate() error {
+	var errs ValidationErrors
+	q.validate(&errs, "")
+	return errs.err()
+}
+
+func (m Message) validate(errs *ValidationErrors, field string) {
+	switch {
+	case m.Text == "" && m.Attachment == nil:
+		errs.add(field, "one of text and attachment must be set")
+	case m.Text != "" && m.Attachment != nil:
+		errs.add(field, "only one of text and attachment can be set")
+	}
+	errs.maxLength(joinField(field, "text"), m.Text, maxTextLength)
+	errs.maxLength(joinField(field, "metadata"), m.Metadata, maxPayloadLength)
+	if m.Attachment != nil {
+		m.Attachment.validate(errs, joinField(field, "attachment"))
+	}
+
+	errs.maxItems(joinField(field, "quick_replies"), len(m.QuickReplies), maxQuickReplies)
+	for i, quickReply := range m.QuickReplies {
+		quickReply.validate(errs, indexField(joinField(field, "quick_replies"), i))
+	}
+}
+
+func (a Attachment) validate(errs *ValidationErrors, field string) {
+	if a.Type == "" {
+		errs.add(joinField(field, "type"), "is required")
+	}
+	if a.Type != AttachmentTypeTemplate {
+		return
+	}
+
+	payload := joinField(field, "payload")
+	elements := joinField(payload, "elements")
+	switch a.Payload.TemplateType {
+	case "":
+		errs.add(joinField(payload, "template_type"), "is required")
+	case TemplateTypeGeneric:
+		if len(a.Payload.Elements) == 0 {
+			errs.add(elements, "is required")
+		}
+		errs.maxItems(elements, len(a.Payload.Elements), genericMaxElements)
+		for i, element := range a.Payload.Elements {
+			errs.required(indexField(elements, i)+".title", element.Title)
+		}
+	case TemplateTypeButton:
+		errs.required(joinField(payload, "text"), a.Payload.Text)
+		errs.maxLength(joinField(payload, "text"), a.Payload.Text, maxButtonTemplateTextLength)
+		if len(a.Payload.Buttons) == 0 {
+			errs.add(joinField(payload, "buttons"), "is required")
+		}
+	case TemplateTypeMedia:
+		if len(a.Payload.Elements) != 1 {
+			errs.add(elements, "must have exactly 1 item")
+		}
+		for i, element := range a.Payload.Elements {
+			errs.maxItems(indexField(elements, i)+".buttons", len(element.Buttons), mediaMaxButtons)
+		}
+	}
+
+	errs.maxLength(joinField(payload, "payload"), a.Payload.Payload, maxPayloadLength)
+	errs.maxItems(joinField(payload, "buttons"), len(a.Payload.Buttons), maxButtons)
+	for i, button := range a.Payload.Buttons {
+		button.validate(errs, indexField(joinField(payload, "buttons"), i))
+	}
+	for i, element := range a.Payload.Elements {
+		element.validate(errs, indexField(elements, i))
+	}
+}
+
+func (e Element) validate(errs *ValidationErrors, field string) {
+	errs.maxLength(joinField(field, "title"), e.Title, maxElementTitleLength)
+	errs.maxLength(joinField(field, "subtitle"), e.Subtitle, maxElementSubtitleLength)
+	errs.maxItems(joinField(field, "buttons"), len(e.Buttons), maxButtons)
+	for i, button := range e.Buttons {
+		button.validate(errs, indexField(joinField(field, "buttons"), i))
+	}
+}
+
+func (b Button) validate(errs *ValidationErrors, field string) {
+	switch b.Type {
+	case "":
+		errs.add(joinField(field, "type"), "is required")
+	case "postback", "phone_number":
+		errs.required(joinField(field, "title"), b.Title)
+		errs.required(joinField(field, "payload"), b.Payload)
+	case "web_url":
+		errs.required(joinField(field, "title"), b.Title)
+		errs.required(joinField(field, "url"), b.URL)
+	}
+	errs.maxLength(joinField(field, "title"), b.Title, maxButtonTitleLength)
+	errs.maxLength(joinField(field, "payload"), b.Payload, maxPayloadLength)
+}
+
+func (q QuickReply) validate(errs *ValidationErrors, field string) {
+	switch q.ContentType {
+	case "":
+		errs.add(joinField(field, "content_type"), "is required")
+	case QuickReplyTypeText:
+		if q.Title == "" && q.ImageURL == "" {
+			errs.add(joinField(field, "title"), "is required without image_url")
+		}
+		errs.required(joinField(field, "payload"), q.Payload)
+	}
+	errs.maxLength(joinField(field, "title"), q.Title, maxQuickReplyTitleLength)
+	errs.maxLength(joinField(field, "payload"), q.Payload, maxPayloadLength)
+}
+
+// Return the path of the field name of the object at path field
+func joinField(field string, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
@@ -0,0 +1,149 @@
+package messenger
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"strings"
+)
+
+const (
+	kHubMode              = "hub.mode"
+	kHubVerifyToken       = "hub.verify_token"
+	kHubChallenge         = "hub.challenge"
+	kHubModeSubscribe     = "subscribe"
+	kSignatureHeader      = "X-Hub-Signature-256"
+	kSignaturePrefix      = "sha256="
+	kMaxWebhookBodyLength = 1 << 20
+)
+
+// WebhookHandler is an http.Handler which receives webhook events from Messenger Platform.
+// https://developers.facebook.com/docs/messenger-platform/webhooks
+//
+// GET requests are treated as the subscribe handshake and answered with hub.challenge when hub.verify_token
+// matches VerifyToken. POST requests must be signed with AppSecret in the X-Hub-Signature-256 header, otherwise
+// they are rejected. A valid event is acknowledged with 200 right away and passed to Handler in a new goroutine,
+// so a slow handler never makes Facebook retry the delivery. The goroutines run concurrently, so two events
+// delivered close together, even from the same user, may be handled out of order. Set Synchronous to handle
+// every event before it is acknowledged instead, in the order of the requests.
+// A panic of Handler is recovered and logged, the event is still acknowledged.
+type WebhookHandler struct {
+	VerifyToken string
+	AppSecret   string
+	Handler     func(event WebhookEvent)
+	Synchronous bool
+}
+
+// Create a new WebhookHandler instance.
+//
+// Input:
+// 		verifyToken: the verify token you entered when setting up the webhook
+// 		appSecret: secret of your app, used to validate the payload signature
+// 		handler: function called for every received WebhookEvent
+// Output:
+// 		A WebhookHandler instance
+func NewWebhookHandler(verifyToken string, appSecret string, handler func(event WebhookEvent)) *WebhookHandler {
+	return &WebhookHandler{
+		VerifyToken: verifyToken,
+		AppSecret:   appSecret,
+		Handler:     handler,
+	}
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.verify(w, r)
+	case http.MethodPost:
+		h.receive(w, r)
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+// Answer the subscribe handshake sent by Facebook when the webhook is set up
+// https://developers.facebook.com/docs/messenger-platform/webhooks#verification-requests
+func (h *WebhookHandler) verify(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get(kHubMode) != kHubModeSubscribe || h.VerifyToken == "" ||
+		!hmac.Equal([]byte(q.Get(kHubVerifyToken)), []byte(h.VerifyToken)) {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(q.Get(kHubChallenge)))
+}
+
+// Validate the signature of an event notification, decode it and hand it to the handler
+// https://developers.facebook.com/docs/messenger-platform/webhooks#event-notifications
+func (h *WebhookHandler) receive(w http.ResponseWriter, r *http.Request) {
+	// Read one byte past the limit to tell a body over the limit from other read errors,
+	// e.g. a client which disconnects
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, kMaxWebhookBodyLength+1))
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	if len(body) > kMaxWebhookBodyLength {
+		http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if !h.validSignature(r.Header.Get(kSignatureHeader), body) {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	if h.Handler == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	if h.Synchronous {
+		h.handle(event)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	go h.handle(event)
+}
+
+// Run the handler, a panic is logged instead of crashing the process
+func (h *WebhookHandler) handle(event WebhookEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("messenger: webhook handler panicked: %v\n%s", r, debug.Stack())
+		}
+	}()
+	h.Handler(event)
+}
+
+// Check the X-Hub-Signature-256 header against the HMAC-SHA256 of the body keyed with the app secret
+func (h *WebhookHandler) validSignature(header string, body []byte) bool {
+	if h.AppSecret == "" || !strings.HasPrefix(header, kSignaturePrefix) {
+		return false
+	}
+
+	signature, err := hex.DecodeString(strings.TrimPrefix(header, kSignaturePrefix))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(h.AppSecret))
+	mac.Write(body)
+	return hmac.Equal(signature, mac.Sum(nil))
+}
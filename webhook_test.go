@@ -0,0 +1,187 @@
+package messenger
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testVerifyToken = "verify-token"
+	testAppSecret   = "app-secret"
+	testEventBody   = `{"object":"page","entry":[{"id":"1","time":1,"messaging":[{"sender":{"id":"2"},"recipient":{"id":"1"},"timestamp":1,"message":{"mid":"m","text":"hi"}}]}]}`
+)
+
+func sign(secret string, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return kSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+func postEvent(h http.Handler, signature string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	if signature != "" {
+		req.Header.Set(kSignatureHeader, signature)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestWebhookSignature(t *testing.T) {
+	tests := []struct {
+		name      string
+		appSecret string
+		signature string
+		body      string
+		status    int
+		handled   bool
+	}{
+		{"valid signature", testAppSecret, sign(testAppSecret, testEventBody), testEventBody, http.StatusOK, true},
+		{"wrong secret", testAppSecret, sign("other-secret", testEventBody), testEventBody, http.StatusUnauthorized, false},
+		{"tampered body", testAppSecret, sign(testAppSecret, testEventBody), strings.Replace(testEventBody, "hi", "ho", 1), http.StatusUnauthorized, false},
+		{"missing header", testAppSecret, "", testEventBody, http.StatusUnauthorized, false},
+		{"missing prefix", testAppSecret, strings.TrimPrefix(sign(testAppSecret, testEventBody), kSignaturePrefix), testEventBody, http.StatusUnauthorized, false},
+		{"sha1 header", testAppSecret, "sha1=" + strings.TrimPrefix(sign(testAppSecret, testEventBody), kSignaturePrefix), testEventBody, http.StatusUnauthorized, false},
+		{"malformed hex", testAppSecret, kSignaturePrefix + "zz", testEventBody, http.StatusUnauthorized, false},
+		{"empty app secret", "", sign("", testEventBody), testEventBody, http.StatusUnauthorized, false},
+		{"invalid json", testAppSecret, sign(testAppSecret, "{"), "{", http.StatusBadRequest, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var handled bool
+			h := NewWebhookHandler(testVerifyToken, tt.appSecret, func(event WebhookEvent) {
+				handled = true
+			})
+			h.Synchronous = true
+
+			rec := postEvent(h, tt.signature, tt.body)
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if handled != tt.handled {
+				t.Errorf("handled = %v, want %v", handled, tt.handled)
+			}
+		})
+	}
+}
+
+func TestWebhookBodyTooLarge(t *testing.T) {
+	body := `{"object":"page","padding":"` + strings.Repeat("a", kMaxWebhookBodyLength) + `"}`
+	h := NewWebhookHandler(testVerifyToken, testAppSecret, func(event WebhookEvent) {
+		t.Error("handler called for a body over the limit")
+	})
+	h.Synchronous = true
+
+	rec := postEvent(h, sign(testAppSecret, body), body)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+// errReader fails every read, like the body of a client which disconnects
+type errReader struct{}
+
+func (errReader) Read(p []byte) (int, error) {
+	return 0, io.ErrUnexpectedEOF
+}
+
+func TestWebhookBodyReadError(t *testing.T) {
+	h := NewWebhookHandler(testVerifyToken, testAppSecret, func(event WebhookEvent) {
+		t.Error("handler called for a body which can not be read")
+	})
+	h.Synchronous = true
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", errReader{})
+	req.Header.Set(kSignatureHeader, sign(testAppSecret, testEventBody))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestWebhookBodyAtLimit(t *testing.T) {
+	prefix, suffix := `{"object":"page","padding":"`, `"}`
+	body := prefix + strings.Repeat("a", kMaxWebhookBodyLength-len(prefix)-len(suffix)) + suffix
+	var handled bool
+	h := NewWebhookHandler(testVerifyToken, testAppSecret, func(event WebhookEvent) {
+		handled = true
+	})
+	h.Synchronous = true
+
+	rec := postEvent(h, sign(testAppSecret, body), body)
+	if rec.Code != http.StatusOK || !handled {
+		t.Errorf("status = %d, handled = %v, want %d and true", rec.Code, handled, http.StatusOK)
+	}
+}
+
+func TestWebhookVerify(t *testing.T) {
+	tests := []struct {
+		name        string
+		verifyToken string
+		query       url.Values
+		status      int
+		body        string
+	}{
+		{"valid token", testVerifyToken, url.Values{kHubMode: {kHubModeSubscribe}, kHubVerifyToken: {testVerifyToken}, kHubChallenge: {"42"}}, http.StatusOK, "42"},
+		{"wrong token", testVerifyToken, url.Values{kHubMode: {kHubModeSubscribe}, kHubVerifyToken: {"wrong"}, kHubChallenge: {"42"}}, http.StatusForbidden, ""},
+		{"missing token", testVerifyToken, url.Values{kHubMode: {kHubModeSubscribe}, kHubChallenge: {"42"}}, http.StatusForbidden, ""},
+		{"wrong mode", testVerifyToken, url.Values{kHubMode: {"unsubscribe"}, kHubVerifyToken: {testVerifyToken}, kHubChallenge: {"42"}}, http.StatusForbidden, ""},
+		{"empty verify token", "", url.Values{kHubMode: {kHubModeSubscribe}, kHubVerifyToken: {""}, kHubChallenge: {"42"}}, http.StatusForbidden, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewWebhookHandler(tt.verifyToken, testAppSecret, nil)
+			req := httptest.NewRequest(http.MethodGet, "/webhook?"+tt.query.Encode(), nil)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if tt.body != "" && rec.Body.String() != tt.body {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.body)
+			}
+		})
+	}
+}
+
+func TestWebhookMethodNotAllowed(t *testing.T) {
+	h := NewWebhookHandler(testVerifyToken, testAppSecret, nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/webhook", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestWebhookHandlerPanic(t *testing.T) {
+	for _, synchronous := range []bool{true, false} {
+		done := make(chan struct{})
+		h := NewWebhookHandler(testVerifyToken, testAppSecret, func(event WebhookEvent) {
+			defer close(done)
+			panic("boom")
+		})
+		h.Synchronous = synchronous
+
+		rec := postEvent(h, sign(testAppSecret, testEventBody), testEventBody)
+		if rec.Code != http.StatusOK {
+			t.Errorf("synchronous=%v: status = %d, want %d", synchronous, rec.Code, http.StatusOK)
+		}
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("synchronous=%v: handler was not called", synchronous)
+		}
+	}
+}
@@ -4,6 +4,9 @@ type AccountLinkingStatus string
 type PolicyEnforcementAction string
 type ReferralSource string
 type ReactionAction string
+type OptinType string
+type NotificationMessagesStatus string
+type UserTokenStatus string
 
 const (
 	AccountLinkingStatusLinked   = AccountLinkingStatus("linked")
@@ -21,6 +24,15 @@ const (
 
 	ReactionActionReact   = ReactionAction("react")
 	ReactionActionUnreact = ReactionAction("unreact")
+
+	OptinTypeOneTimeNotifReq      = OptinType("one_time_notif_req")
+	OptinTypeNotificationMessages = OptinType("notification_messages")
+
+	NotificationMessagesStatusStop   = NotificationMessagesStatus("STOP_NOTIFICATIONS")
+	NotificationMessagesStatusResume = NotificationMessagesStatus("RESUME_NOTIFICATIONS")
+
+	UserTokenStatusRefreshed    = UserTokenStatus("REFRESHED")
+	UserTokenStatusNotRefreshed = UserTokenStatus("NOT_REFRESHED")
 )
 
 type WebhookEvent struct {
@@ -107,8 +119,19 @@ type Handover struct {
 }
 
 type Optin struct {
-	Ref     string `json:"ref"`
-	UserRef string `json:"user_ref"`
+	Ref               string    `json:"ref"`
+	UserRef           string    `json:"user_ref"`
+	Type              OptinType `json:"type,omitempty"`
+	Payload           string    `json:"payload,omitempty"`
+	OneTimeNotifToken string    `json:"one_time_notif_token,omitempty"`
+
+	// Fields of a notification_messages opt-in, the token expiry is in milliseconds since epoch
+	NotificationMessagesToken     string                        `json:"notification_messages_token,omitempty"`
+	TokenExpiryTimestamp          int64                         `json:"token_expiry_timestamp,omitempty"`
+	NotificationMessagesFrequency NotificationMessagesFrequency `json:"notification_messages_frequency,omitempty"`
+	NotificationMessagesTimezone  string                        `json:"notification_messages_timezone,omitempty"`
+	NotificationMessagesStatus    NotificationMessagesStatus    `json:"notification_messages_status,omitempty"`
+	UserTokenStatus               UserTokenStatus               `json:"user_token_status,omitempty"`
 }
 
 type PolicyEnforcement struct {